@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
 
+	"macroquant-intel/backend-go/internal/alerts"
 	"macroquant-intel/backend-go/internal/config"
 	internalhttp "macroquant-intel/backend-go/internal/http"
 	"macroquant-intel/backend-go/internal/services"
@@ -24,8 +29,14 @@ func main() {
 	cfg := config.Load()
 	cache := services.NewCache(cfg)
 	py := services.NewPythonClient(cfg)
+	quotes := services.NewQuotesClient(cfg, cache)
+	symbols := services.NewSymbolRegistry(cfg.SymbolsConfigPath, cfg.SymbolRegistryCap)
 
-	h := internalhttp.NewRouter(cfg, cache, py)
+	go watchSymbolReload(symbols)
+
+	alertStore := startAlerts(cfg, quotes, py)
+
+	h := internalhttp.NewRouter(cfg, cache, py, quotes, symbols, alertStore)
 
 	srv := &http.Server{
 		Addr:              ":" + cfg.Port,
@@ -38,3 +49,67 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// alertSyncInterval governs how often the in-memory rule/mute/target
+// caches are refreshed from Postgres, so CRUD changes made through the
+// alerts API take effect without a restart.
+const alertSyncInterval = 30 * time.Second
+
+// startAlerts wires up the alert-rule subsystem when DATABASE_URL is
+// configured: it opens the Store, seeds the caches, and starts the
+// Runner and its periodic sync in the background. It returns a nil
+// Store when alerts aren't configured, which the handlers and health
+// check treat as "feature disabled". quotes is the same QuotesClient the
+// HTTP handlers use, so the alert resolver and the API never drift into
+// separate Alpaca subscriptions/caches for the same quotes.
+func startAlerts(cfg config.Config, quotes *services.QuotesClient, py *services.PythonClient) *alerts.Store {
+	if cfg.DatabaseURL == "" {
+		return nil
+	}
+	store, err := alerts.NewStore(cfg.DatabaseURL)
+	if err != nil {
+		log.Printf("alerts: failed to connect to %s, alerts disabled: %v", "DATABASE_URL", err)
+		return nil
+	}
+
+	rules := alerts.NewRuleCache()
+	mutes := alerts.NewMuteCache()
+	targets := alerts.NewTargetCache()
+	ctx := context.Background()
+	if err := store.Sync(ctx, rules, mutes, targets); err != nil {
+		log.Printf("alerts: initial sync failed: %v", err)
+	}
+
+	resolver := alerts.NewResolver(quotes, py)
+	runner := alerts.NewRunner(rules, mutes, targets, resolver, store)
+
+	go runner.Run(ctx)
+	go watchAlertSync(ctx, store, rules, mutes, targets)
+
+	return store
+}
+
+func watchAlertSync(ctx context.Context, store *alerts.Store, rules *alerts.RuleCache, mutes *alerts.MuteCache, targets *alerts.TargetCache) {
+	ticker := time.NewTicker(alertSyncInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := store.Sync(ctx, rules, mutes, targets); err != nil {
+			log.Printf("alerts: sync failed: %v", err)
+		}
+	}
+}
+
+// watchSymbolReload reloads the symbol registry from disk whenever the
+// process receives SIGHUP, so an edited watchlist file doesn't require a
+// restart.
+func watchSymbolReload(symbols *services.SymbolRegistry) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	for range sighup {
+		if err := symbols.Reload(); err != nil {
+			log.Printf("symbol registry reload failed: %v", err)
+			continue
+		}
+		log.Printf("symbol registry reloaded")
+	}
+}
@@ -0,0 +1,168 @@
+// Package alerts implements a minimal alert-rule subsystem: rules are
+// evaluated against live metrics on their own interval, matched against
+// active mutes, and on firing (subject to a per-rule cooldown) dispatched
+// through a pluggable Notifier. Rules, mutes, and targets are persisted in
+// Postgres and mirrored into small in-memory caches so the Runner's hot
+// path never blocks on a database round-trip.
+package alerts
+
+import (
+	"sync"
+	"time"
+)
+
+// Rule is a user-defined alert condition, e.g. "quote.AAPL.change_pct <
+// -2.5 for 5m", evaluated every Interval.
+type Rule struct {
+	ID        string
+	Name      string
+	Expr      string
+	Interval  time.Duration
+	Cooldown  time.Duration
+	TargetID  string
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Mute silences a rule's alerts for the window [Start, Until).
+type Mute struct {
+	ID     string
+	RuleID string
+	Reason string
+	Start  time.Time
+	Until  time.Time
+}
+
+// Target is a notification destination a Rule points at via TargetID.
+type Target struct {
+	ID     string
+	Kind   string // "webhook" or "log"
+	Config string // e.g. the webhook URL; unused for "log"
+}
+
+// Event is a single firing of a rule, handed to a Notifier and recorded
+// in history.
+type Event struct {
+	RuleID    string    `json:"rule_id"`
+	RuleName  string    `json:"rule_name"`
+	Metric    string    `json:"metric"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Op        string    `json:"op"`
+	FiredAt   time.Time `json:"fired_at"`
+}
+
+// RuleCache is a read-mostly in-memory mirror of the rules in Postgres,
+// refreshed wholesale on each sync tick via Replace.
+type RuleCache struct {
+	mu    sync.RWMutex
+	items map[string]Rule
+}
+
+func NewRuleCache() *RuleCache { return &RuleCache{items: make(map[string]Rule)} }
+
+func (c *RuleCache) Replace(rules []Rule) {
+	items := make(map[string]Rule, len(rules))
+	for _, r := range rules {
+		items[r.ID] = r
+	}
+	c.mu.Lock()
+	c.items = items
+	c.mu.Unlock()
+}
+
+func (c *RuleCache) List() []Rule {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]Rule, 0, len(c.items))
+	for _, r := range c.items {
+		out = append(out, r)
+	}
+	return out
+}
+
+func (c *RuleCache) Get(id string) (Rule, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	r, ok := c.items[id]
+	return r, ok
+}
+
+// MuteCache mirrors active and upcoming mutes from Postgres.
+type MuteCache struct {
+	mu    sync.RWMutex
+	items map[string]Mute
+}
+
+func NewMuteCache() *MuteCache { return &MuteCache{items: make(map[string]Mute)} }
+
+func (c *MuteCache) Replace(mutes []Mute) {
+	items := make(map[string]Mute, len(mutes))
+	for _, m := range mutes {
+		items[m.ID] = m
+	}
+	c.mu.Lock()
+	c.items = items
+	c.mu.Unlock()
+}
+
+func (c *MuteCache) List() []Mute {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]Mute, 0, len(c.items))
+	for _, m := range c.items {
+		out = append(out, m)
+	}
+	return out
+}
+
+// Active reports whether any mute covers ruleID at instant at.
+func (c *MuteCache) Active(ruleID string, at time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, m := range c.items {
+		if m.RuleID != ruleID {
+			continue
+		}
+		if (at.Equal(m.Start) || at.After(m.Start)) && at.Before(m.Until) {
+			return true
+		}
+	}
+	return false
+}
+
+// TargetCache mirrors notification targets from Postgres.
+type TargetCache struct {
+	mu    sync.RWMutex
+	items map[string]Target
+}
+
+func NewTargetCache() *TargetCache { return &TargetCache{items: make(map[string]Target)} }
+
+func (c *TargetCache) Replace(targets []Target) {
+	items := make(map[string]Target, len(targets))
+	for _, t := range targets {
+		items[t.ID] = t
+	}
+	c.mu.Lock()
+	c.items = items
+	c.mu.Unlock()
+}
+
+func (c *TargetCache) List() []Target {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]Target, 0, len(c.items))
+	for _, t := range c.items {
+		out = append(out, t)
+	}
+	return out
+}
+
+func (c *TargetCache) Get(id string) (Target, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	t, ok := c.items[id]
+	return t, ok
+}
@@ -0,0 +1,69 @@
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expr is a parsed "metric op number [for duration]" rule expression,
+// e.g. "quote.AAPL.change_pct < -2.5 for 5m".
+type Expr struct {
+	Metric    string
+	Op        string
+	Threshold float64
+	For       time.Duration
+}
+
+var validOps = map[string]bool{"<": true, "<=": true, ">": true, ">=": true, "==": true, "!=": true}
+
+// ParseExpr parses the minimal grammar supported by rule expressions:
+// "metric op number" with an optional trailing "for duration".
+func ParseExpr(raw string) (Expr, error) {
+	fields := strings.Fields(raw)
+	if len(fields) < 3 {
+		return Expr{}, fmt.Errorf("alerts: expression %q needs at least metric, op, and number", raw)
+	}
+	metric, op := fields[0], fields[1]
+	if !validOps[op] {
+		return Expr{}, fmt.Errorf("alerts: unsupported operator %q", op)
+	}
+	threshold, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return Expr{}, fmt.Errorf("alerts: invalid threshold %q: %w", fields[2], err)
+	}
+	expr := Expr{Metric: metric, Op: op, Threshold: threshold}
+
+	if len(fields) > 3 {
+		if len(fields) != 5 || fields[3] != "for" {
+			return Expr{}, fmt.Errorf("alerts: expected \"for <duration>\" after the threshold, got %q", strings.Join(fields[3:], " "))
+		}
+		d, err := time.ParseDuration(fields[4])
+		if err != nil {
+			return Expr{}, fmt.Errorf("alerts: invalid duration %q: %w", fields[4], err)
+		}
+		expr.For = d
+	}
+	return expr, nil
+}
+
+// Fires reports whether value satisfies the expression's comparison.
+func (e Expr) Fires(value float64) bool {
+	switch e.Op {
+	case "<":
+		return value < e.Threshold
+	case "<=":
+		return value <= e.Threshold
+	case ">":
+		return value > e.Threshold
+	case ">=":
+		return value >= e.Threshold
+	case "==":
+		return value == e.Threshold
+	case "!=":
+		return value != e.Threshold
+	default:
+		return false
+	}
+}
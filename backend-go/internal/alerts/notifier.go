@@ -0,0 +1,65 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Notifier delivers a fired Event somewhere.
+type Notifier interface {
+	Notify(ctx context.Context, ev Event) error
+}
+
+// LogNotifier writes fired events to the standard logger. It's the
+// fallback for targets with no webhook configured.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(_ context.Context, ev Event) error {
+	log.Printf("alert fired: rule=%s metric=%s value=%g %s %g", ev.RuleName, ev.Metric, ev.Value, ev.Op, ev.Threshold)
+	return nil
+}
+
+// WebhookNotifier POSTs the event as JSON to a configured URL.
+type WebhookNotifier struct {
+	url string
+	hc  *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, hc: &http.Client{Timeout: 5 * time.Second}}
+}
+
+func (w *WebhookNotifier) Notify(ctx context.Context, ev Event) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := w.hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("alerts: webhook %s returned %s", w.url, res.Status)
+	}
+	return nil
+}
+
+// notifierFor picks the Notifier a target describes, falling back to
+// LogNotifier for an unknown target or a webhook target missing its URL.
+func notifierFor(target Target) Notifier {
+	if target.Kind == "webhook" && target.Config != "" {
+		return NewWebhookNotifier(target.Config)
+	}
+	return LogNotifier{}
+}
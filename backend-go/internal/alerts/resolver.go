@@ -0,0 +1,121 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"macroquant-intel/backend-go/internal/services"
+)
+
+// Resolver resolves a metric name to its current value by calling the
+// same service clients the HTTP handlers use, so a fired alert reflects
+// exactly what a client would see.
+type Resolver struct {
+	quotes *services.QuotesClient
+	py     *services.PythonClient
+}
+
+func NewResolver(quotes *services.QuotesClient, py *services.PythonClient) *Resolver {
+	return &Resolver{quotes: quotes, py: py}
+}
+
+// Resolve dispatches on the metric's namespace (the part before the first
+// "."): "quote.<symbol>.<field>", "forecast.<tf>.<target>.<field>", or
+// "event.<hours>.<field>".
+func (r *Resolver) Resolve(ctx context.Context, metric string) (float64, error) {
+	parts := strings.Split(metric, ".")
+	if len(parts) < 2 {
+		return 0, fmt.Errorf("alerts: metric %q has no namespace", metric)
+	}
+	switch parts[0] {
+	case "quote":
+		return r.resolveQuote(ctx, parts)
+	case "forecast":
+		return r.resolveForecast(ctx, parts)
+	case "event":
+		return r.resolveEvent(ctx, parts)
+	default:
+		return 0, fmt.Errorf("alerts: unknown metric namespace %q", parts[0])
+	}
+}
+
+// resolveQuote handles "quote.<symbol>.<field>", field one of price,
+// change_pct.
+func (r *Resolver) resolveQuote(ctx context.Context, parts []string) (float64, error) {
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("alerts: quote metric needs quote.<symbol>.<field>, got %q", strings.Join(parts, "."))
+	}
+	symbol, field := parts[1], parts[2]
+	quotes, err := r.quotes.Fetch(ctx, []string{symbol})
+	if err != nil {
+		return 0, err
+	}
+	q, ok := quotes[symbol]
+	if !ok {
+		return 0, fmt.Errorf("alerts: no quote for %s", symbol)
+	}
+	switch field {
+	case "price":
+		return q.Price, nil
+	case "change_pct":
+		return q.ChangePct, nil
+	default:
+		return 0, fmt.Errorf("alerts: unknown quote field %q", field)
+	}
+}
+
+// resolveForecast handles "forecast.<tf>.<target>.<field>" by calling
+// /forecasts/latest and pulling field out of the JSON response.
+func (r *Resolver) resolveForecast(ctx context.Context, parts []string) (float64, error) {
+	if len(parts) != 4 {
+		return 0, fmt.Errorf("alerts: forecast metric needs forecast.<tf>.<target>.<field>, got %q", strings.Join(parts, "."))
+	}
+	tf, target, field := parts[1], parts[2], parts[3]
+	var out map[string]any
+	path := fmt.Sprintf("/forecasts/latest?tf=%s&target=%s", tf, target)
+	status, err := r.py.FetchJSONWithStatus(ctx, path, &out)
+	if err != nil {
+		return 0, err
+	}
+	if status >= 400 {
+		return 0, fmt.Errorf("alerts: forecast fetch failed with status %d", status)
+	}
+	v, ok := out[field]
+	if !ok {
+		return 0, fmt.Errorf("alerts: forecast response missing field %q", field)
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("alerts: forecast field %q is not numeric", field)
+	}
+	return f, nil
+}
+
+// resolveEvent handles "event.<hours>.<field>" by calling
+// /events/latest?hours=<hours> and pulling field out of the JSON response,
+// mirroring resolveForecast.
+func (r *Resolver) resolveEvent(ctx context.Context, parts []string) (float64, error) {
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("alerts: event metric needs event.<hours>.<field>, got %q", strings.Join(parts, "."))
+	}
+	hours, field := parts[1], parts[2]
+	var out map[string]any
+	path := fmt.Sprintf("/events/latest?hours=%s", hours)
+	status, err := r.py.FetchJSONWithStatus(ctx, path, &out)
+	if err != nil {
+		return 0, err
+	}
+	if status >= 400 {
+		return 0, fmt.Errorf("alerts: event fetch failed with status %d", status)
+	}
+	v, ok := out[field]
+	if !ok {
+		return 0, fmt.Errorf("alerts: event response missing field %q", field)
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("alerts: event field %q is not numeric", field)
+	}
+	return f, nil
+}
@@ -0,0 +1,135 @@
+package alerts
+
+import (
+	"context"
+	"time"
+)
+
+// HistoryRecorder persists fired events. Store implements it when backed
+// by Postgres; a nil HistoryRecorder still notifies, it just doesn't
+// record history.
+type HistoryRecorder interface {
+	RecordEvent(ctx context.Context, ev Event) error
+}
+
+// scanInterval bounds how late a rule's own Interval can run: Run rescans
+// the rule set this often and evaluates any rule whose Interval has
+// elapsed since it last ran. A single scanning goroutine (rather than one
+// per rule) means rules can be added, edited, or removed without having
+// to start or stop per-rule goroutines.
+const scanInterval = 1 * time.Second
+
+// Runner evaluates every enabled rule on its own interval, respecting
+// mutes and a per-rule cooldown, and dispatches fired events through the
+// rule's target. It holds no exported mutable state, so it's safe to
+// start once from main and run until its context is canceled.
+type Runner struct {
+	rules    *RuleCache
+	mutes    *MuteCache
+	targets  *TargetCache
+	resolver *Resolver
+	history  HistoryRecorder
+
+	notifierFor func(Target) Notifier
+
+	lastEval map[string]time.Time
+	pending  map[string]time.Time // rule ID -> when its condition first became true
+	lastFire map[string]time.Time // rule ID -> last time it fired
+}
+
+func NewRunner(rules *RuleCache, mutes *MuteCache, targets *TargetCache, resolver *Resolver, history HistoryRecorder) *Runner {
+	return &Runner{
+		rules:       rules,
+		mutes:       mutes,
+		targets:     targets,
+		resolver:    resolver,
+		history:     history,
+		notifierFor: notifierFor,
+		lastEval:    make(map[string]time.Time),
+		pending:     make(map[string]time.Time),
+		lastFire:    make(map[string]time.Time),
+	}
+}
+
+// Run scans the rule set every scanInterval until ctx is canceled.
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(scanInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.scan(ctx, time.Now())
+		}
+	}
+}
+
+func (r *Runner) scan(ctx context.Context, now time.Time) {
+	for _, rule := range r.rules.List() {
+		if !rule.Enabled {
+			continue
+		}
+		interval := rule.Interval
+		if interval <= 0 {
+			interval = scanInterval
+		}
+		if last, ok := r.lastEval[rule.ID]; ok && now.Sub(last) < interval {
+			continue
+		}
+		r.lastEval[rule.ID] = now
+		r.evaluateRule(ctx, rule, now)
+	}
+}
+
+func (r *Runner) evaluateRule(ctx context.Context, rule Rule, now time.Time) {
+	expr, err := ParseExpr(rule.Expr)
+	if err != nil {
+		return
+	}
+	value, err := r.resolver.Resolve(ctx, expr.Metric)
+	if err != nil {
+		return
+	}
+
+	if !expr.Fires(value) {
+		delete(r.pending, rule.ID)
+		return
+	}
+
+	if expr.For > 0 {
+		since, ok := r.pending[rule.ID]
+		if !ok {
+			r.pending[rule.ID] = now
+			return
+		}
+		if now.Sub(since) < expr.For {
+			return
+		}
+	}
+
+	if r.mutes.Active(rule.ID, now) {
+		return
+	}
+
+	if last, ok := r.lastFire[rule.ID]; ok && rule.Cooldown > 0 && now.Sub(last) < rule.Cooldown {
+		return
+	}
+	r.lastFire[rule.ID] = now
+
+	ev := Event{
+		RuleID:    rule.ID,
+		RuleName:  rule.Name,
+		Metric:    expr.Metric,
+		Value:     value,
+		Threshold: expr.Threshold,
+		Op:        expr.Op,
+		FiredAt:   now,
+	}
+
+	target, _ := r.targets.Get(rule.TargetID)
+	_ = r.notifierFor(target).Notify(ctx, ev)
+	if r.history != nil {
+		_ = r.history.RecordEvent(ctx, ev)
+	}
+}
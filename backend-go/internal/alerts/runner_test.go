@@ -0,0 +1,221 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"macroquant-intel/backend-go/internal/config"
+	"macroquant-intel/backend-go/internal/services"
+)
+
+// captureNotifier records every Notify call so tests can assert on what
+// fired without going over the network.
+type captureNotifier struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (c *captureNotifier) Notify(_ context.Context, ev Event) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events = append(c.events, ev)
+	return nil
+}
+
+func (c *captureNotifier) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.events)
+}
+
+// newTestRunner builds a Runner backed by a fake /forecasts/latest server
+// returning forecastValue for every request, with notifications captured
+// instead of dispatched.
+func newTestRunner(t *testing.T, forecastValue float64) (*Runner, *captureNotifier, *RuleCache) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"predicted_change_pct": forecastValue})
+	}))
+	t.Cleanup(srv.Close)
+
+	py := services.NewPythonClient(config.Config{PyBaseURL: srv.URL})
+	resolver := NewResolver(nil, py)
+
+	rules := NewRuleCache()
+	mutes := NewMuteCache()
+	targets := NewTargetCache()
+	runner := NewRunner(rules, mutes, targets, resolver, nil)
+
+	capture := &captureNotifier{}
+	runner.notifierFor = func(Target) Notifier { return capture }
+
+	return runner, capture, rules
+}
+
+func TestRunnerFiresWhenConditionCrossesThreshold(t *testing.T) {
+	runner, capture, rules := newTestRunner(t, -3.0)
+	rules.Replace([]Rule{{
+		ID:      "r1",
+		Name:    "forecast drop",
+		Expr:    "forecast.1d.AAPL.predicted_change_pct < -2",
+		Enabled: true,
+	}})
+
+	runner.scan(context.Background(), time.Now())
+
+	if capture.count() != 1 {
+		t.Fatalf("expected 1 fired event, got %d", capture.count())
+	}
+}
+
+func TestRunnerDoesNotFireWhenConditionNotMet(t *testing.T) {
+	runner, capture, rules := newTestRunner(t, -1.0)
+	rules.Replace([]Rule{{
+		ID:      "r1",
+		Name:    "forecast drop",
+		Expr:    "forecast.1d.AAPL.predicted_change_pct < -2",
+		Enabled: true,
+	}})
+
+	runner.scan(context.Background(), time.Now())
+
+	if capture.count() != 0 {
+		t.Fatalf("expected no fired events, got %d", capture.count())
+	}
+}
+
+func TestRunnerRespectsCooldown(t *testing.T) {
+	runner, capture, rules := newTestRunner(t, -3.0)
+	rules.Replace([]Rule{{
+		ID:       "r1",
+		Name:     "forecast drop",
+		Expr:     "forecast.1d.AAPL.predicted_change_pct < -2",
+		Cooldown: time.Minute,
+		Enabled:  true,
+	}})
+
+	now := time.Now()
+	runner.evaluateRule(context.Background(), rules.List()[0], now)
+	runner.evaluateRule(context.Background(), rules.List()[0], now.Add(time.Second))
+
+	if capture.count() != 1 {
+		t.Fatalf("expected cooldown to suppress the second fire, got %d events", capture.count())
+	}
+
+	runner.evaluateRule(context.Background(), rules.List()[0], now.Add(2*time.Minute))
+	if capture.count() != 2 {
+		t.Fatalf("expected a fire once the cooldown elapsed, got %d events", capture.count())
+	}
+}
+
+func TestRunnerSuppressesFiringDuringActiveMute(t *testing.T) {
+	runner, capture, rules := newTestRunner(t, -3.0)
+	rules.Replace([]Rule{{
+		ID:      "r1",
+		Name:    "forecast drop",
+		Expr:    "forecast.1d.AAPL.predicted_change_pct < -2",
+		Enabled: true,
+	}})
+
+	now := time.Now()
+	runner.mutes.Replace([]Mute{{
+		ID:     "m1",
+		RuleID: "r1",
+		Start:  now.Add(-time.Minute),
+		Until:  now.Add(time.Minute),
+	}})
+
+	runner.evaluateRule(context.Background(), rules.List()[0], now)
+
+	if capture.count() != 0 {
+		t.Fatalf("expected the active mute to suppress firing, got %d events", capture.count())
+	}
+}
+
+func TestRunnerRequiresConditionSustainedForDuration(t *testing.T) {
+	runner, capture, rules := newTestRunner(t, -3.0)
+	rules.Replace([]Rule{{
+		ID:      "r1",
+		Name:    "sustained forecast drop",
+		Expr:    "forecast.1d.AAPL.predicted_change_pct < -2 for 2m",
+		Enabled: true,
+	}})
+
+	now := time.Now()
+	rule := rules.List()[0]
+
+	runner.evaluateRule(context.Background(), rule, now)
+	if capture.count() != 0 {
+		t.Fatalf("expected no fire before the sustain window elapses, got %d events", capture.count())
+	}
+
+	runner.evaluateRule(context.Background(), rule, now.Add(time.Minute))
+	if capture.count() != 0 {
+		t.Fatalf("expected no fire before the sustain window elapses, got %d events", capture.count())
+	}
+
+	runner.evaluateRule(context.Background(), rule, now.Add(3*time.Minute))
+	if capture.count() != 1 {
+		t.Fatalf("expected a fire once the condition held for the sustain window, got %d events", capture.count())
+	}
+}
+
+func TestResolverResolvesEventNamespace(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/events/latest" {
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"count": 5.0})
+	}))
+	defer srv.Close()
+
+	py := services.NewPythonClient(config.Config{PyBaseURL: srv.URL})
+	resolver := NewResolver(nil, py)
+
+	got, err := resolver.Resolve(context.Background(), "event.24.count")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if got != 5.0 {
+		t.Fatalf("expected 5.0, got %v", got)
+	}
+}
+
+func TestRunnerFiresOnEventNamespaceRule(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"count": 12.0})
+	}))
+	defer srv.Close()
+
+	py := services.NewPythonClient(config.Config{PyBaseURL: srv.URL})
+	resolver := NewResolver(nil, py)
+
+	rules := NewRuleCache()
+	mutes := NewMuteCache()
+	targets := NewTargetCache()
+	runner := NewRunner(rules, mutes, targets, resolver, nil)
+
+	capture := &captureNotifier{}
+	runner.notifierFor = func(Target) Notifier { return capture }
+
+	rules.Replace([]Rule{{
+		ID:      "r1",
+		Name:    "event spike",
+		Expr:    "event.24.count > 10",
+		Enabled: true,
+	}})
+
+	runner.scan(context.Background(), time.Now())
+
+	if capture.count() != 1 {
+		t.Fatalf("expected 1 fired event, got %d", capture.count())
+	}
+}
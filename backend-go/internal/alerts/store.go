@@ -0,0 +1,232 @@
+package alerts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// Store is the Postgres-backed persistence layer for rules, mutes,
+// targets, and fired-event history. This repo has no migration
+// framework, so NewStore bootstraps its tables inline the first time it
+// connects, the same way MemoryCache and RedisCache are self-contained
+// rather than relying on an external setup step.
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(databaseURL string) (*Store, error) {
+	db, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	s := &Store{db: db}
+	if err := s.bootstrap(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) bootstrap(ctx context.Context) error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS alert_targets (
+			id TEXT PRIMARY KEY,
+			kind TEXT NOT NULL,
+			config TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS alert_rules (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			expr TEXT NOT NULL,
+			interval_seconds INTEGER NOT NULL,
+			cooldown_seconds INTEGER NOT NULL,
+			target_id TEXT NOT NULL,
+			enabled BOOLEAN NOT NULL DEFAULT true,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`,
+		`CREATE TABLE IF NOT EXISTS alert_mutes (
+			id TEXT PRIMARY KEY,
+			rule_id TEXT NOT NULL,
+			reason TEXT NOT NULL,
+			starts_at TIMESTAMPTZ NOT NULL,
+			until_at TIMESTAMPTZ NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS alert_history (
+			id BIGSERIAL PRIMARY KEY,
+			rule_id TEXT NOT NULL,
+			rule_name TEXT NOT NULL,
+			metric TEXT NOT NULL,
+			value DOUBLE PRECISION NOT NULL,
+			threshold DOUBLE PRECISION NOT NULL,
+			op TEXT NOT NULL,
+			fired_at TIMESTAMPTZ NOT NULL
+		)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("alerts: bootstrap schema: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying database connection.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Ping reports whether the database is reachable, for Health's
+// DepsStatus.
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *Store) LoadRules(ctx context.Context) ([]Rule, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name, expr, interval_seconds, cooldown_seconds, target_id, enabled, created_at, updated_at FROM alert_rules`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Rule
+	for rows.Next() {
+		var r Rule
+		var intervalSec, cooldownSec int
+		if err := rows.Scan(&r.ID, &r.Name, &r.Expr, &intervalSec, &cooldownSec, &r.TargetID, &r.Enabled, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		r.Interval = time.Duration(intervalSec) * time.Second
+		r.Cooldown = time.Duration(cooldownSec) * time.Second
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) CreateRule(ctx context.Context, r Rule) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO alert_rules (id, name, expr, interval_seconds, cooldown_seconds, target_id, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		r.ID, r.Name, r.Expr, int(r.Interval.Seconds()), int(r.Cooldown.Seconds()), r.TargetID, r.Enabled)
+	return err
+}
+
+func (s *Store) UpdateRule(ctx context.Context, r Rule) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE alert_rules
+		SET name = $2, expr = $3, interval_seconds = $4, cooldown_seconds = $5, target_id = $6, enabled = $7, updated_at = now()
+		WHERE id = $1`,
+		r.ID, r.Name, r.Expr, int(r.Interval.Seconds()), int(r.Cooldown.Seconds()), r.TargetID, r.Enabled)
+	return err
+}
+
+func (s *Store) DeleteRule(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = $1`, id)
+	return err
+}
+
+func (s *Store) LoadMutes(ctx context.Context) ([]Mute, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, rule_id, reason, starts_at, until_at FROM alert_mutes`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Mute
+	for rows.Next() {
+		var m Mute
+		if err := rows.Scan(&m.ID, &m.RuleID, &m.Reason, &m.Start, &m.Until); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) CreateMute(ctx context.Context, m Mute) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO alert_mutes (id, rule_id, reason, starts_at, until_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		m.ID, m.RuleID, m.Reason, m.Start, m.Until)
+	return err
+}
+
+func (s *Store) DeleteMute(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM alert_mutes WHERE id = $1`, id)
+	return err
+}
+
+func (s *Store) LoadTargets(ctx context.Context) ([]Target, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, kind, config FROM alert_targets`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Target
+	for rows.Next() {
+		var t Target
+		if err := rows.Scan(&t.ID, &t.Kind, &t.Config); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// RecordEvent implements HistoryRecorder.
+func (s *Store) RecordEvent(ctx context.Context, ev Event) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO alert_history (rule_id, rule_name, metric, value, threshold, op, fired_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		ev.RuleID, ev.RuleName, ev.Metric, ev.Value, ev.Threshold, ev.Op, ev.FiredAt)
+	return err
+}
+
+// History returns the most recent fired events, newest first, capped at
+// limit.
+func (s *Store) History(ctx context.Context, limit int) ([]Event, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT rule_id, rule_name, metric, value, threshold, op, fired_at
+		FROM alert_history ORDER BY fired_at DESC LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Event
+	for rows.Next() {
+		var ev Event
+		if err := rows.Scan(&ev.RuleID, &ev.RuleName, &ev.Metric, &ev.Value, &ev.Threshold, &ev.Op, &ev.FiredAt); err != nil {
+			return nil, err
+		}
+		out = append(out, ev)
+	}
+	return out, rows.Err()
+}
+
+// Sync refreshes rules, mutes, and targets from Postgres into the given
+// caches. Call it on a timer (e.g. from main alongside the Runner) so
+// CRUD changes take effect without a restart.
+func (s *Store) Sync(ctx context.Context, rules *RuleCache, mutes *MuteCache, targets *TargetCache) error {
+	r, err := s.LoadRules(ctx)
+	if err != nil {
+		return err
+	}
+	m, err := s.LoadMutes(ctx)
+	if err != nil {
+		return err
+	}
+	t, err := s.LoadTargets(ctx)
+	if err != nil {
+		return err
+	}
+	rules.Replace(r)
+	mutes.Replace(m)
+	targets.Replace(t)
+	return nil
+}
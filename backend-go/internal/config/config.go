@@ -3,44 +3,137 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	Port              string
-	PyBaseURL         string
-	RedisURL          string
-	CacheTTLIntel     time.Duration
-	CacheTTLIntelHard time.Duration
-	CacheTTLNews      time.Duration
-	CacheTTLMarket    time.Duration
-	CacheTTLDerv      time.Duration
-	RequestTimeout    time.Duration
-	IntelTimeout      time.Duration
-	PortfolioTimeout  time.Duration
-	RateLimitPerMin   int
-	CircuitFailLimit  int
-	CircuitCooldown   time.Duration
-	MaxWatchlist      int
+	Port                       string
+	PyBaseURL                  string
+	RedisURL                   string
+	CacheTTLIntel              time.Duration
+	CacheTTLIntelHard          time.Duration
+	CacheTTLNews               time.Duration
+	CacheTTLMarket             time.Duration
+	CacheTTLDerv               time.Duration
+	RequestTimeout             time.Duration
+	IntelTimeout               time.Duration
+	PortfolioTimeout           time.Duration
+	RateLimitPerMin            int
+	CircuitFailLimit           int
+	CircuitCooldown            time.Duration
+	CircuitCooldownMax         time.Duration
+	MaxInflightPerUpstream     int
+	MaxWatchlist               int
+	SSEWriteTimeout            time.Duration
+	SSERetryMs                 int
+	MetricsBasicAuth           string
+	SymbolsConfigPath          string
+	SymbolRegistryCap          int
+	DefaultStreamCategories    []string
+	MemoryCacheMaxEntries      int
+	MemoryCacheSweepInterval   time.Duration
+	AlpacaStreamEnabled        bool
+	AlpacaAPIKey               string
+	AlpacaAPISecret            string
+	AlpacaFeed                 string
+	NATSURL                    string
+	RedisKeyPrefix             string
+	NearCacheMaxEntries        int
+	DatabaseURL                string
+	EmbeddingsURL              string
+	CacheTTLEmbeddings         time.Duration
+	NewsRerankTopN             int
+	NewsRankWeight             float64
+	NewsBM25K1                 float64
+	NewsBM25B                  float64
+	CacheTTLBars               time.Duration
+	EventStudyLookbackDays     int
+	EventStudyBenchmark        string
+	ProviderDegradedAfter      time.Duration
+	RateLimitBurst             int
+	RateLimitPortfolioPerMin   int
+	RateLimitDerivativesPerMin int
+	GlobalConcurrencyLimit     int
+	DerivativesEnabledVenues   []string
+	DerivativesRateLimitWindow time.Duration
+	DerivativesRateLimitMax    int
+	DerivativesCoolOff         time.Duration
+	DerivativesRefreshAhead    time.Duration
+	DerivativesRefreshPoolSize int
+	DeliveryQueueDir           string
+	DeliveryQueueSenders       int
+	IntelStreamDeadline        time.Duration
+	IntelStreamHeartbeat       time.Duration
+	HedgePercentile            float64
+	HedgeFallbackDelay         time.Duration
+	HedgeMinSamples            int
 }
 
 func Load() Config {
 	return Config{
-		Port:              getEnv("PORT", "8080"),
-		PyBaseURL:         getEnv("PY_INTEL_BASE_URL", "http://localhost:8001"),
-		RedisURL:          getEnv("REDIS_URL", "redis://localhost:6379"),
-		CacheTTLIntel:     getEnvDuration("CACHE_TTL_INTEL", 30*time.Second),
-		CacheTTLIntelHard: getEnvDuration("CACHE_TTL_INTEL_HARD", 120*time.Second),
-		CacheTTLNews:      getEnvDuration("CACHE_TTL_NEWS", 60*time.Second),
-		CacheTTLMarket:    getEnvDuration("CACHE_TTL_MARKET", 20*time.Second),
-		CacheTTLDerv:      getEnvDuration("CACHE_TTL_DERIV", 90*time.Second),
-		RequestTimeout:    getEnvDuration("PY_REQUEST_TIMEOUT", 12*time.Second),
-		IntelTimeout:      getEnvDuration("PY_INTEL_TIMEOUT", 90*time.Second),
-		PortfolioTimeout:  getEnvDuration("PY_PORTFOLIO_TIMEOUT", 75*time.Second),
-		RateLimitPerMin:   getEnvInt("RATE_LIMIT_PER_MIN", 120),
-		CircuitFailLimit:  getEnvInt("CIRCUIT_FAIL_LIMIT", 3),
-		CircuitCooldown:   getEnvDuration("CIRCUIT_COOLDOWN", 20*time.Second),
-		MaxWatchlist:      getEnvInt("MAX_WATCHLIST", 30),
+		Port:                   getEnv("PORT", "8080"),
+		PyBaseURL:              getEnv("PY_INTEL_BASE_URL", "http://localhost:8001"),
+		RedisURL:               getEnv("REDIS_URL", "redis://localhost:6379"),
+		CacheTTLIntel:          getEnvDuration("CACHE_TTL_INTEL", 30*time.Second),
+		CacheTTLIntelHard:      getEnvDuration("CACHE_TTL_INTEL_HARD", 120*time.Second),
+		CacheTTLNews:           getEnvDuration("CACHE_TTL_NEWS", 60*time.Second),
+		CacheTTLMarket:         getEnvDuration("CACHE_TTL_MARKET", 20*time.Second),
+		CacheTTLDerv:           getEnvDuration("CACHE_TTL_DERIV", 90*time.Second),
+		RequestTimeout:         getEnvDuration("PY_REQUEST_TIMEOUT", 12*time.Second),
+		IntelTimeout:           getEnvDuration("PY_INTEL_TIMEOUT", 90*time.Second),
+		PortfolioTimeout:       getEnvDuration("PY_PORTFOLIO_TIMEOUT", 75*time.Second),
+		RateLimitPerMin:        getEnvInt("RATE_LIMIT_PER_MIN", 120),
+		CircuitFailLimit:       getEnvInt("CIRCUIT_FAIL_LIMIT", 3),
+		CircuitCooldown:        getEnvDuration("CIRCUIT_COOLDOWN", 20*time.Second),
+		CircuitCooldownMax:     getEnvDuration("CIRCUIT_COOLDOWN_MAX", 5*time.Minute),
+		MaxInflightPerUpstream: getEnvInt("MAX_INFLIGHT_PER_UPSTREAM", 8),
+		MaxWatchlist:           getEnvInt("MAX_WATCHLIST", 30),
+		SSEWriteTimeout:        getEnvDuration("SSE_WRITE_TIMEOUT", 5*time.Second),
+		SSERetryMs:             getEnvInt("SSE_RETRY_MS", 15000),
+		MetricsBasicAuth:       getEnv("METRICS_BASIC_AUTH", ""),
+		SymbolsConfigPath:      getEnv("SYMBOLS_CONFIG_PATH", ""),
+		SymbolRegistryCap:      getEnvInt("SYMBOL_REGISTRY_CAP", 250),
+		DefaultStreamCategories: getEnvList("DEFAULT_STREAM_CATEGORIES", []string{
+			"crypto", "us_mega", "energy", "defense", "tr_equity", "financials", "industrials", "materials", "israel_security", "fx",
+		}),
+		MemoryCacheMaxEntries:      getEnvInt("MEMORY_CACHE_MAX_ENTRIES", 10000),
+		MemoryCacheSweepInterval:   getEnvDuration("MEMORY_CACHE_SWEEP_INTERVAL", 30*time.Second),
+		AlpacaStreamEnabled:        getEnvBool("ALPACA_STREAM_ENABLED", false),
+		AlpacaAPIKey:               getEnv("ALPACA_API_KEY", ""),
+		AlpacaAPISecret:            getEnv("ALPACA_API_SECRET", ""),
+		AlpacaFeed:                 getEnv("ALPACA_FEED", "iex"),
+		NATSURL:                    getEnv("NATS_URL", ""),
+		RedisKeyPrefix:             getEnv("REDIS_KEY_PREFIX", ""),
+		NearCacheMaxEntries:        getEnvInt("NEAR_CACHE_MAX_ENTRIES", 500),
+		DatabaseURL:                getEnv("DATABASE_URL", ""),
+		EmbeddingsURL:              getEnv("EMBEDDINGS_URL", ""),
+		CacheTTLEmbeddings:         getEnvDuration("CACHE_TTL_EMBEDDINGS", 24*time.Hour),
+		NewsRerankTopN:             getEnvInt("NEWS_RERANK_TOP_N", 20),
+		NewsRankWeight:             getEnvFloat("NEWS_RANK_WEIGHT", 0.5),
+		NewsBM25K1:                 getEnvFloat("NEWS_BM25_K1", 1.5),
+		NewsBM25B:                  getEnvFloat("NEWS_BM25_B", 0.75),
+		CacheTTLBars:               getEnvDuration("CACHE_TTL_BARS", 6*time.Hour),
+		EventStudyLookbackDays:     getEnvInt("EVENT_STUDY_LOOKBACK_DAYS", 20),
+		EventStudyBenchmark:        getEnv("EVENT_STUDY_BENCHMARK", "SPY"),
+		ProviderDegradedAfter:      getEnvDuration("PROVIDER_DEGRADED_AFTER", 2*time.Minute),
+		RateLimitBurst:             getEnvInt("RATE_LIMIT_BURST", 30),
+		RateLimitPortfolioPerMin:   getEnvInt("RATE_LIMIT_PORTFOLIO_PER_MIN", 30),
+		RateLimitDerivativesPerMin: getEnvInt("RATE_LIMIT_DERIVATIVES_PER_MIN", 60),
+		GlobalConcurrencyLimit:     getEnvInt("GLOBAL_CONCURRENCY_LIMIT", 64),
+		DerivativesEnabledVenues:   getEnvList("DERIVATIVES_ENABLED_VENUES", []string{"binance", "okx", "bybit"}),
+		DerivativesRateLimitWindow: getEnvDuration("DERIVATIVES_RATE_LIMIT_WINDOW", 5*time.Minute),
+		DerivativesRateLimitMax:    getEnvInt("DERIVATIVES_RATE_LIMIT_MAX", 3),
+		DerivativesCoolOff:         getEnvDuration("DERIVATIVES_COOLOFF", 10*time.Minute),
+		DerivativesRefreshAhead:    getEnvDuration("DERIVATIVES_REFRESH_AHEAD", 15*time.Second),
+		DerivativesRefreshPoolSize: getEnvInt("DERIVATIVES_REFRESH_POOL_SIZE", 4),
+		DeliveryQueueDir:           getEnv("DELIVERY_QUEUE_DIR", "./data/delivery"),
+		DeliveryQueueSenders:       getEnvInt("DELIVERY_QUEUE_SENDERS", 4),
+		IntelStreamDeadline:        getEnvDuration("INTEL_STREAM_DEADLINE", 5*time.Minute),
+		IntelStreamHeartbeat:       getEnvDuration("INTEL_STREAM_HEARTBEAT", 30*time.Second),
+		HedgePercentile:            getEnvFloat("HEDGE_PERCENTILE", 0.95),
+		HedgeFallbackDelay:         getEnvDuration("HEDGE_FALLBACK_DELAY", 150*time.Millisecond),
+		HedgeMinSamples:            getEnvInt("HEDGE_MIN_SAMPLES", 8),
 	}
 }
 
@@ -64,6 +157,45 @@ func getEnvInt(key string, def int) int {
 	return i
 }
 
+func getEnvList(key string, def []string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	parts := strings.Split(v, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	if len(out) == 0 {
+		return def
+	}
+	return out
+}
+
+func getEnvFloat(key string, def float64) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return def
+	}
+	return f
+}
+
+func getEnvBool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	return v == "1" || v == "true" || v == "yes" || v == "on"
+}
+
 func getEnvDuration(key string, def time.Duration) time.Duration {
 	v := os.Getenv(key)
 	if v == "" {
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"macroquant-intel/backend-go/internal/alerts"
+)
+
+// randomAlertID mints an ID for a newly created rule, mute, or target. It
+// follows the same crypto/rand hex-ID convention as the intel service's
+// instance IDs.
+func randomAlertID(prefix string) string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%s-%d", prefix, time.Now().UnixNano())
+	}
+	return prefix + "-" + hex.EncodeToString(b)
+}
+
+// alertRuleBody is the JSON shape accepted by AlertRules' POST and PUT,
+// using seconds for durations since Go's time.Duration doesn't round-trip
+// through JSON.
+type alertRuleBody struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Expr            string `json:"expr"`
+	IntervalSeconds int    `json:"interval_seconds"`
+	CooldownSeconds int    `json:"cooldown_seconds"`
+	TargetID        string `json:"target_id"`
+	Enabled         bool   `json:"enabled"`
+}
+
+// AlertRules handles CRUD for alert rules: GET lists them, POST creates,
+// PUT updates (the "id" field selects the row), DELETE removes the rule
+// named by the "id" query parameter. It's backed by Postgres directly
+// rather than proxied to the Python service, since alert rules are a
+// Go-only subsystem.
+func (a *API) AlertRules(w http.ResponseWriter, r *http.Request) {
+	if a.alertStore == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"error": "alerts_not_configured"})
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		rules, err := a.alertStore.LoadRules(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusBadGateway, map[string]any{"error": "store_error"})
+			return
+		}
+		writeJSON(w, http.StatusOK, rules)
+		return
+	case http.MethodPost:
+		var body alertRuleBody
+		if !decodeJSONBody(w, r, &body) {
+			return
+		}
+		if _, err := alerts.ParseExpr(body.Expr); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_expr"})
+			return
+		}
+		rule := alerts.Rule{
+			ID:       randomAlertID("rule"),
+			Name:     body.Name,
+			Expr:     body.Expr,
+			Interval: time.Duration(body.IntervalSeconds) * time.Second,
+			Cooldown: time.Duration(body.CooldownSeconds) * time.Second,
+			TargetID: body.TargetID,
+			Enabled:  body.Enabled,
+		}
+		if err := a.alertStore.CreateRule(r.Context(), rule); err != nil {
+			writeJSON(w, http.StatusBadGateway, map[string]any{"error": "store_error"})
+			return
+		}
+		writeJSON(w, http.StatusOK, rule)
+		return
+	case http.MethodPut:
+		var body alertRuleBody
+		if !decodeJSONBody(w, r, &body) {
+			return
+		}
+		if body.ID == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "missing_id"})
+			return
+		}
+		if _, err := alerts.ParseExpr(body.Expr); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_expr"})
+			return
+		}
+		rule := alerts.Rule{
+			ID:       body.ID,
+			Name:     body.Name,
+			Expr:     body.Expr,
+			Interval: time.Duration(body.IntervalSeconds) * time.Second,
+			Cooldown: time.Duration(body.CooldownSeconds) * time.Second,
+			TargetID: body.TargetID,
+			Enabled:  body.Enabled,
+		}
+		if err := a.alertStore.UpdateRule(r.Context(), rule); err != nil {
+			writeJSON(w, http.StatusBadGateway, map[string]any{"error": "store_error"})
+			return
+		}
+		writeJSON(w, http.StatusOK, rule)
+		return
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "missing_id"})
+			return
+		}
+		if err := a.alertStore.DeleteRule(r.Context(), id); err != nil {
+			writeJSON(w, http.StatusBadGateway, map[string]any{"error": "store_error"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"deleted": id})
+		return
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"error": "method_not_allowed"})
+	}
+}
+
+// alertMuteBody is the JSON shape accepted by AlertMutes' POST.
+type alertMuteBody struct {
+	RuleID string    `json:"rule_id"`
+	Reason string    `json:"reason"`
+	Start  time.Time `json:"start"`
+	Until  time.Time `json:"until"`
+}
+
+// AlertMutes handles CRUD for mute windows: GET lists them, POST creates
+// one, DELETE removes the mute named by the "id" query parameter.
+func (a *API) AlertMutes(w http.ResponseWriter, r *http.Request) {
+	if a.alertStore == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"error": "alerts_not_configured"})
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		mutes, err := a.alertStore.LoadMutes(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusBadGateway, map[string]any{"error": "store_error"})
+			return
+		}
+		writeJSON(w, http.StatusOK, mutes)
+		return
+	case http.MethodPost:
+		var body alertMuteBody
+		if !decodeJSONBody(w, r, &body) {
+			return
+		}
+		if body.RuleID == "" || !body.Until.After(body.Start) {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_mute_window"})
+			return
+		}
+		mute := alerts.Mute{
+			ID:     randomAlertID("mute"),
+			RuleID: body.RuleID,
+			Reason: body.Reason,
+			Start:  body.Start,
+			Until:  body.Until,
+		}
+		if err := a.alertStore.CreateMute(r.Context(), mute); err != nil {
+			writeJSON(w, http.StatusBadGateway, map[string]any{"error": "store_error"})
+			return
+		}
+		writeJSON(w, http.StatusOK, mute)
+		return
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "missing_id"})
+			return
+		}
+		if err := a.alertStore.DeleteMute(r.Context(), id); err != nil {
+			writeJSON(w, http.StatusBadGateway, map[string]any{"error": "store_error"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"deleted": id})
+		return
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"error": "method_not_allowed"})
+	}
+}
+
+// AlertHistory returns the most recently fired alert events, newest
+// first, bounded by an optional "limit" query parameter.
+func (a *API) AlertHistory(w http.ResponseWriter, r *http.Request) {
+	if a.alertStore == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"error": "alerts_not_configured"})
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"error": "method_not_allowed"})
+		return
+	}
+	limit := parseIntParam(r.URL.Query().Get("limit"), 50, 1, 500)
+	events, err := a.alertStore.History(r.Context(), limit)
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]any{"error": "store_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, events)
+}
+
+// decodeJSONBody reads and JSON-decodes r.Body into dst, writing a 400
+// response and returning false on failure.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, 16<<10)
+	defer r.Body.Close()
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]any{"error": "payload_too_large"})
+		return false
+	}
+	if err := json.Unmarshal(payload, dst); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_json"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// deadlineTimer backs a single read or write deadline with a *time.Timer,
+// modeled on net.Conn's SetDeadline: Reset can be called any number of
+// times as the deadline slides forward without allocating a new context
+// each time, the way an SSE writer re-arms its write deadline on every
+// tick. Each Reset stops the previous timer and arms a new one; the timer
+// itself is not reused. Done returns the channel that closes when the
+// most recently set deadline expires.
+type deadlineTimer struct {
+	mu    sync.Mutex
+	timer *time.Timer
+	done  chan struct{}
+}
+
+func newDeadlineTimer() *deadlineTimer {
+	return &deadlineTimer{done: make(chan struct{})}
+}
+
+// Reset arms (or re-arms) the deadline for t. Done keeps its current
+// channel while that channel is still open, so a watcher already waiting
+// on it sees the new deadline; only once a deadline has expired does
+// Reset replace Done with a fresh channel. A zero t disarms the timer
+// without closing Done. A t that has already passed closes Done
+// immediately.
+func (d *deadlineTimer) Reset(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	select {
+	case <-d.done:
+		d.done = make(chan struct{})
+	default:
+	}
+	if t.IsZero() {
+		return
+	}
+	done := d.done
+	dur := time.Until(t)
+	if dur <= 0 {
+		close(done)
+		return
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(dur, func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		// A timer stopped or replaced after it already fired must not
+		// close the channel the newer deadline is using.
+		if d.timer == timer {
+			d.timer = nil
+			close(done)
+		}
+	})
+	d.timer = timer
+}
+
+// Done returns the channel that closes when the deadline set by the most
+// recent Reset expires.
+func (d *deadlineTimer) Done() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.done
+}
+
+// Stop releases the underlying timer without closing Done, for when the
+// guarded operation finished before the deadline.
+func (d *deadlineTimer) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+}
+
+// deadlineContext derives a context from parent that is additionally
+// canceled when dt's deadline expires, giving a plain upstream call
+// read-deadline semantics: unlike context.WithTimeout, the caller can
+// still call dt.Reset to push the deadline out — e.g. once a long-poll
+// to Python starts returning partial data — without discarding and
+// recreating the context. The returned cancel func must be called to
+// release the watcher goroutine.
+func deadlineContext(parent context.Context, dt *deadlineTimer, timeout time.Duration) (context.Context, context.CancelFunc) {
+	dt.Reset(time.Now().Add(timeout))
+	ctx, cancel := context.WithCancel(parent)
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-dt.Done():
+			cancel()
+		case <-ctx.Done():
+		case <-stop:
+		}
+	}()
+	return ctx, func() {
+		close(stop)
+		dt.Stop()
+		cancel()
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeadlineTimerExpiresDoneChannel(t *testing.T) {
+	dt := newDeadlineTimer()
+	dt.Reset(time.Now().Add(20 * time.Millisecond))
+
+	select {
+	case <-dt.Done():
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for deadline to expire")
+	}
+}
+
+func TestDeadlineTimerResetPushesDeadlineOutWithoutNewChannel(t *testing.T) {
+	dt := newDeadlineTimer()
+	dt.Reset(time.Now().Add(20 * time.Millisecond))
+	done := dt.Done()
+
+	// Push the deadline out repeatedly before it fires, simulating partial
+	// progress on a long-poll. Done should keep tracking the latest Reset.
+	for i := 0; i < 3; i++ {
+		time.Sleep(5 * time.Millisecond)
+		dt.Reset(time.Now().Add(50 * time.Millisecond))
+	}
+
+	select {
+	case <-done:
+		t.Fatal("stale Done channel from before the resets fired early")
+	default:
+	}
+
+	select {
+	case <-dt.Done():
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the final deadline to expire")
+	}
+}
+
+func TestDeadlineTimerStopPreventsExpiry(t *testing.T) {
+	dt := newDeadlineTimer()
+	dt.Reset(time.Now().Add(20 * time.Millisecond))
+	dt.Stop()
+
+	select {
+	case <-dt.Done():
+		t.Fatal("Done closed after Stop")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -23,6 +23,16 @@ func (a *API) Derivatives(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := contextTimeout(r.Context(), a.cfg.RequestTimeout)
 	defer cancel()
 
+	if exchange == "all" {
+		agg, err := a.deriv.GetAggregated(ctx, symbol, r.URL.Query().Get("weighting"))
+		if err != nil && len(agg.Venues) == 0 {
+			writeJSON(w, http.StatusServiceUnavailable, agg)
+			return
+		}
+		writeJSON(w, http.StatusOK, agg)
+		return
+	}
+
 	resp, health := a.deriv.Get(ctx, exchange, symbol)
 	resp.Exchange = exchange
 	resp.Symbol = symbol
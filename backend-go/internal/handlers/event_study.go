@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+
+	"macroquant-intel/backend-go/internal/models"
+	"macroquant-intel/backend-go/internal/services"
+)
+
+// maxEventStudyEvents/maxEventStudyAssets bound how much of the event
+// feed fillEventStudy computes reactions for, so a degraded Python
+// upstream doesn't turn one Intel request into dozens of price-history
+// fetches.
+const (
+	maxEventStudyEvents = 5
+	maxEventStudyAssets = 2
+)
+
+// fillEventStudy populates resp.Flow.EventStudy from resp.EventFeed via
+// the Go-side EventStudy service when Python hasn't already filled it in,
+// so FlowPanel.EventStudy still has something useful during a Python
+// degradation. It's a best-effort fallback: any event or asset that fails
+// to compute is just skipped.
+func (a *API) fillEventStudy(ctx context.Context, resp *models.IntelResponse) {
+	if a.eventStudy == nil || len(resp.Flow.EventStudy) > 0 {
+		return
+	}
+
+	candidates := make([]models.EventItem, 0, maxEventStudyEvents)
+	for _, group := range [][]models.EventItem{resp.EventFeed.Company, resp.EventFeed.Sector} {
+		for _, ev := range group {
+			if len(ev.ImpactedAssets) == 0 || ev.Ts == "" {
+				continue
+			}
+			candidates = append(candidates, ev)
+			if len(candidates) >= maxEventStudyEvents {
+				break
+			}
+		}
+		if len(candidates) >= maxEventStudyEvents {
+			break
+		}
+	}
+
+	points := make([]models.EventPoint, 0, len(candidates))
+	for _, ev := range candidates {
+		eventID := eventStudyEventID(ev)
+		reactions := make(map[string]models.EventReactions, maxEventStudyAssets)
+		assetCount := 0
+		for _, asset := range ev.ImpactedAssets {
+			if asset.SymbolOrID == "" {
+				continue
+			}
+			r, err := a.eventStudy.Compute(ctx, services.EventStudyInput{
+				EventID:        eventID,
+				Asset:          asset.SymbolOrID,
+				PublishedAtISO: ev.Ts,
+			})
+			if err != nil {
+				continue
+			}
+			reactions[asset.SymbolOrID] = r
+			assetCount++
+			if assetCount >= maxEventStudyAssets {
+				break
+			}
+		}
+		if len(reactions) == 0 {
+			continue
+		}
+		points = append(points, models.EventPoint{
+			ID:             eventID,
+			Title:          ev.Title,
+			TsISO:          ev.Ts,
+			EventID:        eventID,
+			Headline:       ev.Title,
+			PublishedAtUTC: ev.Ts,
+			Scope:          ev.EventCategory,
+			Reactions:      reactions,
+			Combined:       services.CombineReactions(reactions),
+		})
+	}
+	resp.Flow.EventStudy = points
+}
+
+// eventStudyEventID derives a stable id for an EventItem that doesn't
+// carry one of its own, from its DedupClusterID if present or else a hash
+// of its title and timestamp.
+func eventStudyEventID(ev models.EventItem) string {
+	if ev.DedupClusterID != "" {
+		return ev.DedupClusterID
+	}
+	sum := sha1.Sum([]byte(ev.Title + "|" + ev.Ts))
+	return fmt.Sprintf("evt_%s", hex.EncodeToString(sum[:8]))
+}
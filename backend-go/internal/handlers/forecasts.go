@@ -5,9 +5,12 @@ import (
 	"net/http"
 	"time"
 
+	"macroquant-intel/backend-go/internal/metrics"
 	"macroquant-intel/backend-go/internal/services"
 )
 
+const eventsCachePrefix = "events"
+
 func (a *API) ForecastLatest(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 	tf := q.Get("tf")
@@ -50,6 +53,7 @@ func (a *API) EventsLatest(w http.ResponseWriter, r *http.Request) {
 
 	if a.cache != nil {
 		if _, ok := a.cache.Get(r.Context(), downKey); ok {
+			metrics.CacheEvents.WithLabelValues(eventsCachePrefix, "down").Inc()
 			if b, ok := a.cache.Get(r.Context(), staleKey); ok {
 				var cached map[string]any
 				if err := services.UnmarshalCache(b, &cached); err == nil {
@@ -65,18 +69,20 @@ func (a *API) EventsLatest(w http.ResponseWriter, r *http.Request) {
 		if b, ok := a.cache.Get(r.Context(), cacheKey); ok {
 			var cached map[string]any
 			if err := services.UnmarshalCache(b, &cached); err == nil {
+				metrics.CacheEvents.WithLabelValues(eventsCachePrefix, "hit").Inc()
 				cached["data_status"] = "cached"
 				writeJSON(w, http.StatusOK, cached)
 				return
 			}
 		}
+		metrics.CacheEvents.WithLabelValues(eventsCachePrefix, "miss").Inc()
 	}
 
 	timeout := a.cfg.RequestTimeout
 	if timeout > 4*time.Second {
 		timeout = 4 * time.Second
 	}
-	ctx, cancel := timeboxed(r, timeout)
+	ctx, cancel := deadlineContext(r.Context(), newDeadlineTimer(), timeout)
 	defer cancel()
 
 	var out map[string]any
@@ -99,6 +105,7 @@ func (a *API) EventsLatest(w http.ResponseWriter, r *http.Request) {
 		if b, ok := a.cache.Get(r.Context(), staleKey); ok {
 			var cached map[string]any
 			if err := services.UnmarshalCache(b, &cached); err == nil {
+				metrics.CacheEvents.WithLabelValues(eventsCachePrefix, "stale").Inc()
 				cached["data_status"] = "stale"
 				cached["stale_reason"] = "analytics_unreachable"
 				writeJSON(w, http.StatusOK, cached)
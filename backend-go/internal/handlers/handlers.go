@@ -11,26 +11,71 @@ import (
 	"strings"
 	"time"
 
+	"macroquant-intel/backend-go/internal/alerts"
 	"macroquant-intel/backend-go/internal/config"
 	"macroquant-intel/backend-go/internal/models"
 	"macroquant-intel/backend-go/internal/services"
 )
 
 type API struct {
-	cfg   config.Config
-	cache services.Cache
-	py    *services.PythonClient
-	deriv *services.DerivativesClient
-	quotes *services.QuotesClient
+	cfg         config.Config
+	cache       services.Cache
+	py          *services.PythonClient
+	deriv       *services.DerivativesClient
+	quotes      *services.QuotesClient
+	intel       *services.IntelService
+	symbols     *services.SymbolRegistry
+	alertStore  *alerts.Store
+	newsRankers map[string]services.NewsRanker
+	eventStudy  *services.EventStudy
+	health      *services.HealthRegistry
 }
 
-func New(cfg config.Config, cache services.Cache, py *services.PythonClient) *API {
+// healthRegistrar is implemented by Cache backends that can record their
+// call outcomes in a HealthRegistry (currently RedisCache and nearCache).
+// MemoryCache-only deployments don't implement it, and New skips wiring
+// health recording for them rather than reporting a "redis" dependency
+// that was never configured.
+type healthRegistrar interface {
+	SetHealthRegistry(h *services.HealthRegistry)
+}
+
+func New(cfg config.Config, cache services.Cache, py *services.PythonClient, quotes *services.QuotesClient, symbols *services.SymbolRegistry, alertStore *alerts.Store) *API {
+	health := services.NewHealthRegistry(cfg)
+	py.SetHealthRegistry(health)
+	if hr, ok := cache.(healthRegistrar); ok {
+		hr.SetHealthRegistry(health)
+	}
+	prices := services.NewHTTPPriceProvider(cfg, cache)
+	prices.SetHealthRegistry(health)
+
 	return &API{
-		cfg:   cfg,
-		cache: cache,
-		py:    py,
-		deriv: services.NewDerivativesClient(cfg, cache),
-		quotes: services.NewQuotesClient(cfg, cache),
+		cfg:         cfg,
+		cache:       cache,
+		py:          py,
+		deriv:       services.NewDerivativesClient(cfg, cache),
+		quotes:      quotes,
+		intel:       services.NewIntelService(cfg, cache, py),
+		symbols:     symbols,
+		alertStore:  alertStore,
+		newsRankers: newNewsRankers(cfg, cache),
+		eventStudy:  services.NewEventStudy(cfg, cache, prices),
+		health:      health,
+	}
+}
+
+// newNewsRankers builds the rank=bm25|hybrid strategies News dispatches
+// on. "hybrid" degrades to plain BM25 order when cfg.EmbeddingsURL isn't
+// configured, so it's always safe to select.
+func newNewsRankers(cfg config.Config, cache services.Cache) map[string]services.NewsRanker {
+	bm25 := services.NewBM25Ranker(cfg.NewsBM25K1, cfg.NewsBM25B)
+	var embed services.EmbeddingClient
+	if cfg.EmbeddingsURL != "" {
+		embed = services.NewHTTPEmbeddingClient(cfg, cache)
+	}
+	return map[string]services.NewsRanker{
+		"bm25":   bm25,
+		"hybrid": services.NewEmbeddingReranker(bm25, embed, cfg.NewsRerankTopN, cfg.NewsRankWeight),
 	}
 }
 
@@ -147,6 +192,24 @@ func parseWatchlist(raw string, max int) []string {
 	return out
 }
 
+func parseCategories(raw string, def []string) []string {
+	if raw == "" {
+		return def
+	}
+	parts := strings.Split(raw, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	if len(out) == 0 {
+		return def
+	}
+	return out
+}
+
 func parseIntParam(v string, def int, min int, max int) int {
 	if v == "" {
 		return def
@@ -168,3 +231,27 @@ func parseIntParam(v string, def int, min int, max int) int {
 func nowISO() string {
 	return time.Now().UTC().Format(time.RFC3339)
 }
+
+// parseLastEventID parses an SSE Last-Event-ID header value as the seq
+// number it encodes: its leading non-negative integer, so a StreamIntel
+// `<seq>:<hash>` id yields its seq. It returns 0 (meaning "no cursor") if
+// the value is empty or does not start with such an integer.
+func parseLastEventID(raw string) uint64 {
+	if raw == "" {
+		return 0
+	}
+	var out uint64
+	if _, err := fmt.Sscanf(raw, "%d", &out); err != nil {
+		return 0
+	}
+	return out
+}
+
+// sseRetryMs is the client reconnect hint sent as the `retry:` field at
+// the start of every SSE stream.
+func (a *API) sseRetryMs() int {
+	if a.cfg.SSERetryMs <= 0 {
+		return 15000
+	}
+	return a.cfg.SSERetryMs
+}
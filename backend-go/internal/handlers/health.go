@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"macroquant-intel/backend-go/internal/models"
+	"macroquant-intel/backend-go/internal/services"
 )
 
 func (a *API) Health(w http.ResponseWriter, r *http.Request) {
@@ -24,6 +25,42 @@ func (a *API) Health(w http.ResponseWriter, r *http.Request) {
 		depsStatus["analytics"] = models.DepStatus{Ok: true}
 	}
 
+	if configured, err := a.intel.NATSStatus(ctx); configured {
+		if err != nil {
+			missing = append(missing, "nats_unreachable")
+			depsStatus["nats"] = models.DepStatus{Ok: false, Error: err.Error()}
+		} else {
+			deps = append(deps, "nats")
+			depsStatus["nats"] = models.DepStatus{Ok: true}
+		}
+	}
+
+	if pinger, ok := a.cache.(services.Pinger); ok {
+		if err := pinger.Ping(ctx); err != nil {
+			missing = append(missing, "redis_unreachable")
+			depsStatus["redis"] = models.DepStatus{Ok: false, Error: err.Error()}
+		} else {
+			deps = append(deps, "redis")
+			depsStatus["redis"] = models.DepStatus{Ok: true}
+		}
+	}
+
+	if a.alertStore != nil {
+		if err := a.alertStore.Ping(ctx); err != nil {
+			missing = append(missing, "alerts_db_unreachable")
+			depsStatus["alerts_db"] = models.DepStatus{Ok: false, Error: err.Error()}
+		} else {
+			deps = append(deps, "alerts_db")
+			depsStatus["alerts_db"] = models.DepStatus{Ok: true}
+		}
+	}
+
+	degraded := false
+	if a.health != nil {
+		mergeProviderHealth(depsStatus, a.health)
+		degraded = a.health.Degraded()
+	}
+
 	resp := models.HealthResponse{
 		Ok:          len(missing) == 0,
 		TsISO:       nowISO(),
@@ -31,6 +68,7 @@ func (a *API) Health(w http.ResponseWriter, r *http.Request) {
 		Version:     os.Getenv("SERVICE_VERSION"),
 		Deps:        deps,
 		DepsStatus:  depsStatus,
+		Degraded:    degraded,
 		DataMissing: missing,
 		Env: map[string]bool{
 			"FINNHUB_API_KEY":       os.Getenv("FINNHUB_API_KEY") != "",
@@ -43,14 +81,36 @@ func (a *API) Health(w http.ResponseWriter, r *http.Request) {
 			"NEXT_PUBLIC_API_BASE":  os.Getenv("NEXT_PUBLIC_API_BASE") != "",
 		},
 		Features: map[string]bool{
-			"finnhub_fallback_enabled":   os.Getenv("FINNHUB_API_KEY") != "",
+			"finnhub_fallback_enabled":    os.Getenv("FINNHUB_API_KEY") != "",
 			"twelvedata_fallback_enabled": os.Getenv("TWELVEDATA_API_KEY") != "",
-			"openai_summaries_enabled": summaryEnabled(),
+			"openai_summaries_enabled":    summaryEnabled(),
+			"alpaca_stream_enabled":       a.cfg.AlpacaStreamEnabled,
 		},
 	}
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// mergeProviderHealth folds health's recorded provider outcomes into
+// depsStatus: existing entries (e.g. "redis", populated above from a live
+// Ping) are enriched with latency/last-good-age, and sources the registry
+// tracks but the live checks above don't (e.g. "python_intel", "prices")
+// are added outright.
+func mergeProviderHealth(depsStatus map[string]models.DepStatus, health *services.HealthRegistry) {
+	providers, _ := health.Snapshot()
+	for _, p := range providers {
+		status := depsStatus[p.Source]
+		status.LatencyMs = p.LatencyMs
+		status.LastGoodAgeS = p.LastGoodAgeS
+		if _, tracked := depsStatus[p.Source]; !tracked {
+			status.Ok = p.Ok
+			if !p.Ok {
+				status.Error = p.ErrorMsg
+			}
+		}
+		depsStatus[p.Source] = status
+	}
+}
+
 func summaryEnabled() bool {
 	flag := os.Getenv("ENABLE_OPENAI_SUMMARY")
 	if flag == "" {
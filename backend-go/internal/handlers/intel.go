@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
 
 	"macroquant-intel/backend-go/internal/models"
+	"macroquant-intel/backend-go/internal/services"
 )
 
 func (a *API) Intel(w http.ResponseWriter, r *http.Request) {
@@ -33,6 +35,9 @@ func (a *API) Intel(w http.ResponseWriter, r *http.Request) {
 		resp = emptyIntel(timeframe, newsTimespan)
 		resp.Debug.Notes = appendUniqueString(resp.Debug.Notes, "python_unreachable")
 		resp.Debug.DataMissing = appendUniqueString(resp.Debug.DataMissing, "analytics")
+		if errors.Is(err, services.ErrCircuitOpen) {
+			resp.Debug.Providers = append(resp.Debug.Providers, circuitOpenProviderDebug(err))
+		}
 		writeJSON(w, http.StatusOK, resp)
 		return
 	}
@@ -45,11 +50,43 @@ func (a *API) Intel(w http.ResponseWriter, r *http.Request) {
 	}
 	if err != nil || meta.Err != "" {
 		resp.Debug.Notes = appendUniqueString(resp.Debug.Notes, "refresh_error")
+		if strings.Contains(meta.Err, services.ErrCircuitOpen.Error()) {
+			resp.Debug.Providers = append(resp.Debug.Providers, circuitOpenProviderDebug(errors.New(meta.Err)))
+		}
 	}
 
+	a.fillEventStudy(ctx, &resp)
+	a.appendProviderHealth(&resp)
+
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// appendProviderHealth adds every source HealthRegistry has recorded since
+// startup to resp.Debug.Providers, plus a rolled-up
+// ProviderMetricsSummary, so clients can see upstream health without
+// hitting /health separately.
+func (a *API) appendProviderHealth(resp *models.IntelResponse) {
+	if a.health == nil {
+		return
+	}
+	providers, summary := a.health.Snapshot()
+	resp.Debug.Providers = append(resp.Debug.Providers, providers...)
+	resp.Debug.ProviderMetricsSummary = summary
+}
+
+// circuitOpenProviderDebug builds the DebugInfo.Providers entry a client
+// sees when the "/intel" upstream circuit is open: degraded_mode=true with
+// no latency or cache info, since no call was actually attempted.
+func circuitOpenProviderDebug(err error) models.ProviderDebug {
+	return models.ProviderDebug{
+		Source:       "python_intel",
+		Ok:           false,
+		DegradedMode: true,
+		ErrorCode:    "circuit_open",
+		ErrorMsg:     err.Error(),
+	}
+}
+
 func emptyIntel(timeframe string, newsTimespan string) models.IntelResponse {
 	return models.IntelResponse{
 		TsISO:        nowISO(),
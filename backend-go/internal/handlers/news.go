@@ -15,11 +15,15 @@ func (a *API) News(w http.ResponseWriter, r *http.Request) {
 	pageSize := parseIntParam(q.Get("pageSize"), 5, 1, 50)
 	filter := strings.TrimSpace(q.Get("filter"))
 	searchText := strings.TrimSpace(q.Get("q"))
+	rank := strings.ToLower(strings.TrimSpace(q.Get("rank")))
+	if rank == "" {
+		rank = "bm25"
+	}
 	newsTimespan := q.Get("newsTimespan")
 	if newsTimespan == "" {
 		newsTimespan = "6h"
 	}
-	cacheKey := "news:v1:" + newsTimespan + ":" + strings.ToLower(filter) + ":" + strings.ToLower(searchText) + ":" + fmt.Sprintf("%d:%d", page, pageSize)
+	cacheKey := "news:v1:" + newsTimespan + ":" + strings.ToLower(filter) + ":" + strings.ToLower(searchText) + ":" + rank + ":" + fmt.Sprintf("%d:%d", page, pageSize)
 	if a.cache != nil {
 		if b, ok := a.cache.Get(r.Context(), cacheKey); ok {
 			var cached models.NewsPageResponse
@@ -39,6 +43,13 @@ func (a *API) News(w http.ResponseWriter, r *http.Request) {
 	}
 	items = applyNewsFilter(items, filter)
 	items = applyNewsSearch(items, searchText)
+	if searchText != "" && rank != "off" {
+		if ranker, ok := a.newsRankers[rank]; ok {
+			if ranked, err := ranker.Rank(r.Context(), searchText, items); err == nil {
+				items = ranked
+			}
+		}
+	}
 
 	total := len(items)
 	start := (page - 1) * pageSize
@@ -31,9 +31,11 @@ func (a *API) PortfolioDailyBrief(w http.ResponseWriter, r *http.Request) {
 	if timeout <= 0 {
 		timeout = a.cfg.RequestTimeout
 	}
-	status, err := a.py.FetchJSONWithStatusTimeout(r.Context(), path, &out, timeout)
+	ctx, cancel := deadlineContext(r.Context(), newDeadlineTimer(), timeout)
+	defer cancel()
+	status, err := a.py.FetchJSONWithStatusTimeout(ctx, path, &out, timeout)
 	if err != nil {
-		writeUpstreamError(w, err, status)
+		writeUpstreamError(w, "py", err, status)
 		return
 	}
 	writeJSON(w, http.StatusOK, out)
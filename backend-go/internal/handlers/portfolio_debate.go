@@ -30,7 +30,7 @@ func (a *API) PortfolioDebate(w http.ResponseWriter, r *http.Request) {
 		var out any
 		status, err := a.py.FetchJSONWithStatus(r.Context(), path, &out)
 		if err != nil {
-			writeUpstreamError(w, err, status)
+			writeUpstreamError(w, "py", err, status)
 			return
 		}
 		writeJSON(w, http.StatusOK, out)
@@ -51,7 +51,7 @@ func (a *API) PortfolioDebate(w http.ResponseWriter, r *http.Request) {
 		var out any
 		status, err := a.py.PostJSONWithStatus(r.Context(), "/api/v1/portfolio/debate", payload, &out)
 		if err != nil {
-			writeUpstreamError(w, err, status)
+			writeUpstreamError(w, "py", err, status)
 			return
 		}
 		writeJSON(w, http.StatusOK, out)
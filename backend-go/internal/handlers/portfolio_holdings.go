@@ -12,7 +12,7 @@ func (a *API) PortfolioHoldings(w http.ResponseWriter, r *http.Request) {
 		var out any
 		status, err := a.py.FetchJSONWithStatus(r.Context(), "/api/v1/portfolio/holdings", &out)
 		if err != nil {
-			writeUpstreamError(w, err, status)
+			writeUpstreamError(w, "py", err, status)
 			return
 		}
 		writeJSON(w, http.StatusOK, out)
@@ -32,7 +32,7 @@ func (a *API) PortfolioHoldings(w http.ResponseWriter, r *http.Request) {
 		var out any
 		status, err := a.py.PostJSONWithStatus(r.Context(), "/api/v1/portfolio/holdings", payload, &out)
 		if err != nil {
-			writeUpstreamError(w, err, status)
+			writeUpstreamError(w, "py", err, status)
 			return
 		}
 		writeJSON(w, http.StatusOK, out)
@@ -51,7 +51,7 @@ func (a *API) PortfolioHoldings(w http.ResponseWriter, r *http.Request) {
 		var out any
 		status, err := a.py.DeleteJSONWithStatus(r.Context(), path, &out)
 		if err != nil {
-			writeUpstreamError(w, err, status)
+			writeUpstreamError(w, "py", err, status)
 			return
 		}
 		writeJSON(w, http.StatusOK, out)
@@ -1,12 +1,13 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
+
+	"macroquant-intel/backend-go/internal/services"
 )
 
 func (a *API) StreamQuotes(w http.ResponseWriter, r *http.Request) {
@@ -31,32 +32,62 @@ func (a *API) StreamQuotes(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("X-Accel-Buffering", "no")
 
-	ticker := time.NewTicker(time.Duration(intervalSec) * time.Second)
-	defer ticker.Stop()
+	lastEventID := parseLastEventID(r.Header.Get("Last-Event-ID"))
+	ch, unsubscribe, replay, replayOK := a.quotes.Subscribe(r.Context(), assets, time.Duration(intervalSec)*time.Second, lastEventID)
+	defer unsubscribe()
+
+	// sseConn evicts a stalled client after sseMaxConsecutiveMisses missed
+	// write deadlines rather than piling up one blocked writer goroutine
+	// per missed write, so a slow/congested client here can't leak
+	// goroutines for the life of the process.
+	conn := newSSEConn(w, flusher)
+	writeTimeout := a.cfg.SSEWriteTimeout
+	if writeTimeout <= 0 {
+		writeTimeout = 5 * time.Second
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := conn.WriteRaw(fmt.Sprintf("retry: %d\n\n", a.sseRetryMs())); err != nil {
+		return
+	}
 
-	send := func() {
-		ctx, cancel := context.WithTimeout(r.Context(), a.cfg.RequestTimeout)
-		defer cancel()
-		quotes, err := a.quotes.Fetch(ctx, assets)
+	send := func(snap services.QuoteSnapshot) bool {
 		payload := map[string]any{
-			"tsISO":  nowISO(),
-			"quotes": quotes,
+			"tsISO":  snap.TsISO,
+			"quotes": snap.Quotes,
 		}
-		if err != nil {
-			payload["error"] = err.Error()
+		if snap.Err != "" {
+			payload["error"] = snap.Err
 		}
 		data, _ := json.Marshal(payload)
-		_, _ = fmt.Fprintf(w, "data: %s\n\n", data)
-		flusher.Flush()
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		return conn.WriteEventWithID(fmt.Sprintf("%d", snap.Seq), "quotes", data) == nil
+	}
+
+	if lastEventID > 0 && !replayOK {
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := conn.WriteEvent("reset", []byte(`{"reason":"cursor_too_old"}`)); err != nil {
+			return
+		}
+	} else {
+		for _, snap := range replay {
+			if !send(snap) {
+				return
+			}
+		}
 	}
 
-	send()
 	for {
 		select {
 		case <-r.Context().Done():
 			return
-		case <-ticker.C:
-			send()
+		case snap, ok := <-ch:
+			if !ok {
+				return
+			}
+			if !send(snap) {
+				return
+			}
 		}
 	}
 }
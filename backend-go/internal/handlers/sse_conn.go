@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// errSlowClient is returned by WriteEvent once the connection has missed
+// sseMaxConsecutiveMisses write deadlines in a row.
+var errSlowClient = errors.New("sse: slow client evicted")
+
+const sseMaxConsecutiveMisses = 2
+
+// sseConn wraps an http.ResponseWriter/http.Flusher pair with a per-write
+// deadline, modeled on the SetWriteDeadline pattern of net.Conn. A write
+// runs on its own goroutine and races against the deadlineTimer armed by
+// SetWriteDeadline, so a stalled TCP peer can pin that goroutine but never
+// the caller. If a deadline is missed while that goroutine is still blocked
+// on the stalled writer, a later WriteRaw call joins the same in-flight
+// goroutine instead of spawning a new one that would write to the same
+// http.ResponseWriter concurrently and unsynchronized — the new frame is
+// dropped in that case, which is consistent with the peer already failing
+// to keep up. After sseMaxConsecutiveMisses deadlines are missed back to
+// back, writes start failing with errSlowClient so the handler can tear the
+// subscription down.
+type sseConn struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+
+	wd *deadlineTimer
+
+	mu          sync.Mutex
+	misses      int
+	pendingDone chan struct{}
+	pendingErr  error
+}
+
+func newSSEConn(w http.ResponseWriter, flusher http.Flusher) *sseConn {
+	return &sseConn{w: w, flusher: flusher, wd: newDeadlineTimer()}
+}
+
+// SetWriteDeadline arms (or re-arms) the deadline backing the next write.
+func (c *sseConn) SetWriteDeadline(t time.Time) {
+	c.wd.Reset(t)
+}
+
+// WriteRaw writes an already-framed SSE payload, racing the write against
+// the deadline set by SetWriteDeadline. If a previous WriteRaw's write
+// goroutine is still blocked on the underlying writer, this call waits on
+// that same goroutine instead of starting a new one (see sseConn's doc
+// comment), so frame is only actually written if no write is in flight.
+func (c *sseConn) WriteRaw(frame string) error {
+	cancelCh := c.wd.Done()
+
+	c.mu.Lock()
+	done := c.pendingDone
+	if done == nil {
+		done = make(chan struct{})
+		c.pendingDone = done
+		go func() {
+			_, err := fmt.Fprint(c.w, frame)
+			if err == nil {
+				c.flusher.Flush()
+			}
+			c.mu.Lock()
+			c.pendingErr = err
+			c.pendingDone = nil
+			c.mu.Unlock()
+			close(done)
+		}()
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-done:
+		c.mu.Lock()
+		err := c.pendingErr
+		c.misses = 0
+		c.mu.Unlock()
+		return err
+	case <-cancelCh:
+		c.mu.Lock()
+		c.misses++
+		miss := c.misses
+		c.mu.Unlock()
+		if miss >= sseMaxConsecutiveMisses {
+			return errSlowClient
+		}
+		return nil
+	}
+}
+
+// WriteEvent frames and writes a single named SSE event. An empty name
+// produces a plain `data:` frame with no `event:` line.
+func (c *sseConn) WriteEvent(name string, data []byte) error {
+	return c.WriteEventWithID("", name, data)
+}
+
+// WriteEventWithID is WriteEvent plus a leading `id:` line, so the client's
+// EventSource tracks it as Last-Event-ID and replays from it on reconnect.
+// An empty id omits the line.
+func (c *sseConn) WriteEventWithID(id string, name string, data []byte) error {
+	frame := ""
+	if id != "" {
+		frame += fmt.Sprintf("id: %s\n", id)
+	}
+	if name != "" {
+		frame += fmt.Sprintf("event: %s\n", name)
+	}
+	frame += fmt.Sprintf("data: %s\n\n", data)
+	return c.WriteRaw(frame)
+}
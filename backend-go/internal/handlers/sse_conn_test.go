@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// stalledWriter is an http.ResponseWriter/http.Flusher pair backed by an
+// io.Pipe whose read side nobody drains, so every Write blocks forever —
+// standing in for a slow client whose TCP receive window never opens.
+type stalledWriter struct {
+	header http.Header
+	pw     *io.PipeWriter
+}
+
+func newStalledWriter() (*stalledWriter, *io.PipeReader) {
+	pr, pw := io.Pipe()
+	return &stalledWriter{header: http.Header{}, pw: pw}, pr
+}
+
+func (s *stalledWriter) Header() http.Header         { return s.header }
+func (s *stalledWriter) Write(p []byte) (int, error) { return s.pw.Write(p) }
+func (s *stalledWriter) WriteHeader(int)             {}
+func (s *stalledWriter) Flush()                      {}
+
+func TestSSEConnWriteRawReturnsWithinDeadlineForStalledClient(t *testing.T) {
+	w, _ := newStalledWriter() // reader intentionally never read from
+	conn := newSSEConn(w, w)
+
+	start := time.Now()
+	conn.SetWriteDeadline(start.Add(30 * time.Millisecond))
+
+	done := make(chan error, 1)
+	go func() { done <- conn.WriteRaw("data: hello\n\n") }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected first missed deadline to be tolerated, got error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WriteRaw did not return within the write deadline")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("WriteRaw took too long to return: %v", elapsed)
+	}
+}
+
+func TestSSEConnEvictsStalledClientAfterRepeatedMisses(t *testing.T) {
+	w, _ := newStalledWriter()
+	conn := newSSEConn(w, w)
+
+	var lastErr error
+	for i := 0; i < sseMaxConsecutiveMisses; i++ {
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Millisecond))
+		lastErr = conn.WriteRaw("data: hello\n\n")
+	}
+
+	if lastErr != errSlowClient {
+		t.Fatalf("expected errSlowClient after %d consecutive misses, got %v", sseMaxConsecutiveMisses, lastErr)
+	}
+}
+
+// TestSSEConnJoinsInFlightWriteInsteadOfLeaking reproduces the leak this
+// regression was filed against: repeated WriteRaw calls against a writer
+// that never unblocks must not spawn a new goroutine per call (they'd all
+// pile up blocked on the same stalled ResponseWriter forever), and must
+// never have two goroutines writing to it at once.
+func TestSSEConnJoinsInFlightWriteInsteadOfLeaking(t *testing.T) {
+	w, _ := newStalledWriter()
+	conn := newSSEConn(w, w)
+
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < sseMaxConsecutiveMisses; i++ {
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Millisecond))
+		conn.WriteRaw("data: hello\n\n")
+	}
+
+	// Give any wrongly-spawned extra goroutines a moment to start before
+	// counting.
+	time.Sleep(20 * time.Millisecond)
+	after := runtime.NumGoroutine()
+
+	if grown := after - before; grown > 1 {
+		t.Fatalf("expected at most 1 new goroutine (the single in-flight write) after %d missed writes, got %d", sseMaxConsecutiveMisses, grown)
+	}
+}
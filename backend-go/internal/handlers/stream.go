@@ -6,8 +6,24 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+
+	"macroquant-intel/backend-go/internal/services"
 )
 
+// streamHeartbeatInterval bounds how long StreamIntel can go without
+// sending anything: if no block has changed and no quote refresh has run
+// in this long, it sends a heartbeat so intermediary proxies and clients
+// with their own idle timeouts don't drop the connection.
+const streamHeartbeatInterval = 20 * time.Second
+
+// StreamIntel streams the Intel snapshot as per-block deltas rather than
+// the full payload on every tick: each of services.IntelBlockNames is
+// tracked by its own hash, and only blocks whose hash changed since the
+// last emit on this connection are sent, as a named `block` event whose
+// SSE id is `<seq>:<hash>` (the snapshot seq, then the block's hash).
+// Reconnecting with Last-Event-ID resumes from that ring-buffered
+// snapshot seq (handled by a.intel.Subscribe) rather than a full
+// resnapshot.
 func (a *API) StreamIntel(w http.ResponseWriter, r *http.Request) {
 	flusher, ok := w.(http.Flusher)
 	if !ok {
@@ -25,178 +41,94 @@ func (a *API) StreamIntel(w http.ResponseWriter, r *http.Request) {
 		newsTimespan = "6h"
 	}
 	watch := parseWatchlist(q.Get("watch"), a.cfg.MaxWatchlist)
+	categories := parseCategories(q.Get("categories"), a.cfg.DefaultStreamCategories)
 
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache, no-transform")
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("X-Accel-Buffering", "no")
 
-	ch, unsubscribe := a.intel.Subscribe(r.Context(), timeframe, newsTimespan, watch, 5*time.Second)
+	lastEventID := parseLastEventID(r.Header.Get("Last-Event-ID"))
+	ch, unsubscribe, replay, replayOK := a.intel.Subscribe(r.Context(), timeframe, newsTimespan, watch, 5*time.Second, lastEventID)
 	defer unsubscribe()
 
-	send := func(meta map[string]any) {
-		ctx, cancel := context.WithTimeout(r.Context(), a.cfg.RequestTimeout)
-		defer cancel()
-		quotes, qerr := a.quotes.Fetch(ctx, []string{
-			"BTC",
-			"ETH",
-			"BTC-USD",
-			"NEAR-USD",
-			"USDTRY=X",
-			"ASTOR.IS",
-			"SOKM.IS",
-			"TUPRS.IS",
-			"ENJSA.IS",
-			"SIL",
-			"AMD",
-			"PLTR",
-			"HL",
-			"AAPL",
-			"MSFT",
-			"AMZN",
-			"GOOGL",
-			"META",
-			"NVDA",
-			"TSLA",
-			"MSTR",
-			"COIN",
-			"ASML.AS",
-			"SAP.DE",
-			"005930.KS",
-			"6758.T",
-			"SHOP.TO",
-			"ADYEN.AS",
-			"NOKIA.HE",
-			"0700.HK",
-			"9988.HK",
-			"XOM",
-			"CVX",
-			"COP",
-			"OXY",
-			"SLB",
-			"EOG",
-			"MPC",
-			"PSX",
-			"VLO",
-			"SHEL",
-			"TTE",
-			"BP",
-			"EQNR",
-			"PBR",
-			"ENB",
-			"SU.TO",
-			"CNQ.TO",
-			"REP.MC",
-			"JPM",
-			"BAC",
-			"WFC",
-			"C",
-			"GS",
-			"MS",
-			"BLK",
-			"SCHW",
-			"AXP",
-			"HSBA.L",
-			"UBSG.SW",
-			"BNP.PA",
-			"DBK.DE",
-			"INGA.AS",
-			"8058.T",
-			"SAN.MC",
-			"BARC.L",
-			"ZURN.SW",
-			"CAT",
-			"DE",
-			"BA",
-			"GE",
-			"HON",
-			"UNP",
-			"UPS",
-			"LMT",
-			"RTX",
-			"SIE.DE",
-			"AIR.PA",
-			"DPW.DE",
-			"VOLV-B.ST",
-			"7203.T",
-			"7267.T",
-			"CP.TO",
-			"6501.T",
-			"SGRO.L",
-			"LIN",
-			"APD",
-			"SHW",
-			"ECL",
-			"DD",
-			"DOW",
-			"NUE",
-			"FCX",
-			"NEM",
-			"BHP.AX",
-			"RIO.AX",
-			"GLEN.L",
-			"ANTO.L",
-			"BAS.DE",
-			"SIKA.SW",
-			"AEM.TO",
-			"NTR.TO",
-			"IVN.AX",
-			"NOC",
-			"GD",
-			"LHX",
-			"HII",
-			"TDG",
-			"AVAV",
-			"KTOS",
-			"BA.L",
-			"RHM.DE",
-			"HO.PA",
-			"LDO.MI",
-			"SAAB-B.ST",
-			"SAF.PA",
-			"HAG.DE",
-			"AM.PA",
-			"ASELS.IS",
-			"OTKAR.IS",
-			"SDTTR.IS",
-			"ALTNY.IS",
-			"ONRYT.IS",
-			"PAPIL.IS",
-			"PATEK.IS",
-			"KATMR.IS",
-			"TMSN.IS",
-			"CHKP",
-			"CYBR",
-			"NICE",
-			"ESLT",
-			"IAI.TA",
-			"ESLT.TA",
-			"NICE.TA",
-			"MGDL.TA",
-			"FIBI.TA",
-		})
-		payload := map[string]any{
-			"tsISO":  meta["tsISO"],
-			"market": meta["market"],
-			"risk":   meta["risk"],
-		}
-		for k, v := range meta {
-			if k == "tsISO" || k == "market" || k == "risk" {
+	// sseConn evicts a stalled client after sseMaxConsecutiveMisses missed
+	// write deadlines rather than piling up one blocked writer goroutine
+	// per missed write, so a naturally slow/congested client here (mobile,
+	// high-latency) can't leak goroutines for the life of the process.
+	conn := newSSEConn(w, flusher)
+	writeTimeout := a.cfg.SSEWriteTimeout
+	if writeTimeout <= 0 {
+		writeTimeout = 5 * time.Second
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := conn.WriteRaw(fmt.Sprintf("retry: %d\n\n", a.sseRetryMs())); err != nil {
+		return
+	}
+
+	lastHash := map[string]string{}
+
+	sendBlocks := func(snap services.IntelSnapshot) bool {
+		blocks := services.IntelBlocks(snap.Resp)
+		for _, name := range services.IntelBlockNames {
+			hash := snap.Resp.BlockHashes[name]
+			if hash != "" && hash == lastHash[name] {
 				continue
 			}
-			payload[k] = v
+			lastHash[name] = hash
+			payload := map[string]any{
+				"block": name,
+				"seq":   snap.Seq,
+				"data":  blocks[name],
+			}
+			if snap.Meta.Err != "" {
+				payload["intel_error"] = snap.Meta.Err
+			}
+			data, _ := json.Marshal(payload)
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteEventWithID(fmt.Sprintf("%d:%s", snap.Seq, hash), "block", data); err != nil {
+				return false
+			}
 		}
-		if len(quotes) > 0 {
-			payload["quotes"] = quotes
+		return true
+	}
+
+	sendQuotes := func() bool {
+		ctx, cancel := context.WithTimeout(r.Context(), a.cfg.RequestTimeout)
+		defer cancel()
+		quotes, err := a.quotes.Fetch(ctx, a.symbols.Resolve(categories, watch))
+		if len(quotes) == 0 && err == nil {
+			return true
 		}
-		if qerr != nil {
-			payload["quotes_error"] = qerr.Error()
+		payload := map[string]any{"quotes": quotes}
+		if err != nil {
+			payload["quotes_error"] = err.Error()
 		}
 		data, _ := json.Marshal(payload)
-		_, _ = fmt.Fprintf(w, "data: %s\n\n", data)
-		flusher.Flush()
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		return conn.WriteEvent("quotes", data) == nil
 	}
 
+	if lastEventID > 0 && !replayOK {
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := conn.WriteEvent("reset", []byte(`{"reason":"cursor_too_old"}`)); err != nil {
+			return
+		}
+	} else {
+		for _, snap := range replay {
+			if !sendBlocks(snap) {
+				return
+			}
+		}
+	}
+	if !sendQuotes() {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
 	for {
 		select {
 		case <-r.Context().Done():
@@ -205,17 +137,18 @@ func (a *API) StreamIntel(w http.ResponseWriter, r *http.Request) {
 			if !ok {
 				return
 			}
-			meta := map[string]any{
-				"tsISO":        snap.Resp.TsISO,
-				"market":       snap.Resp.Market,
-				"risk":         snap.Resp.Risk,
-				"intel_source": snap.Meta.Source,
-				"intel_stale":  snap.Meta.Stale,
+			if !sendBlocks(snap) {
+				return
+			}
+			if !sendQuotes() {
+				return
 			}
-			if snap.Meta.Err != "" {
-				meta["intel_error"] = snap.Meta.Err
+			heartbeat.Reset(streamHeartbeatInterval)
+		case <-heartbeat.C:
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteEvent("heartbeat", []byte(`{}`)); err != nil {
+				return
 			}
-			send(meta)
 		}
 	}
 }
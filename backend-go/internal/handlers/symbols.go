@@ -0,0 +1,29 @@
+package handlers
+
+import "net/http"
+
+// Symbols returns the current symbol registry so the frontend can render a
+// category picker for the quote/intel streams.
+func (a *API) Symbols(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"tsISO":      nowISO(),
+		"categories": a.symbols.Categories(),
+	})
+}
+
+// ReloadSymbols re-reads the symbol registry file in place. It is the HTTP
+// equivalent of sending the process a SIGHUP.
+func (a *API) ReloadSymbols(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"error": "method_not_allowed"})
+		return
+	}
+	if err := a.symbols.Reload(); err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]any{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"tsISO":      nowISO(),
+		"categories": a.symbols.Categories(),
+	})
+}
@@ -6,10 +6,15 @@ import (
 	"net"
 	"net/http"
 
+	"macroquant-intel/backend-go/internal/metrics"
 	"macroquant-intel/backend-go/internal/services"
 )
 
-func writeUpstreamError(w http.ResponseWriter, err error, status int) {
+// writeUpstreamError maps err to an HTTP response and records it against
+// upstream_requests_total under the given client label (py/quotes/deriv).
+func writeUpstreamError(w http.ResponseWriter, client string, err error, status int) {
+	metrics.UpstreamRequests.WithLabelValues(client, upstreamOutcome(err)).Inc()
+
 	var upErr *services.UpstreamError
 	if errors.As(err, &upErr) {
 		if upErr.Status == http.StatusTooManyRequests {
@@ -44,3 +49,28 @@ func writeUpstreamError(w http.ResponseWriter, err error, status int) {
 	}
 	writeJSON(w, http.StatusBadGateway, map[string]any{"error": err.Error()})
 }
+
+// upstreamOutcome classifies err into outcome buckets for the
+// upstream_requests_total counter, following writeUpstreamError's HTTP
+// mapping with one difference: an upstream 408 counts as "timeout" here,
+// while writeUpstreamError answers it like any other 4xx.
+func upstreamOutcome(err error) string {
+	var upErr *services.UpstreamError
+	if errors.As(err, &upErr) {
+		if upErr.Status == http.StatusRequestTimeout || upErr.Status == http.StatusGatewayTimeout {
+			return "timeout"
+		}
+		if upErr.Status >= 400 && upErr.Status < 500 {
+			return "upstream_4xx"
+		}
+		return "upstream_5xx"
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "timeout"
+	}
+	return "network"
+}
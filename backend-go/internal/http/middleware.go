@@ -1,12 +1,20 @@
 package http
 
 import (
+	"encoding/json"
 	"log"
+	"math"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"macroquant-intel/backend-go/internal/config"
+	"macroquant-intel/backend-go/internal/metrics"
+	"macroquant-intel/backend-go/internal/reqlog"
+	"macroquant-intel/backend-go/internal/services"
 )
 
 type statusWriter struct {
@@ -25,13 +33,39 @@ func (s *statusWriter) Flush() {
 	}
 }
 
+// withLogging assigns each request an ID (the incoming X-Request-ID header
+// if present, otherwise a fresh one), echoes it back on the response, and
+// threads a reqlog.Entry through the request's context so downstream
+// upstream calls (PythonClient, DerivativesClient) can record their
+// attempts against it. It emits one structured JSON record per request via
+// reqlog.Logger once the handler returns.
 func withLogging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqID := r.Header.Get("X-Request-ID")
+		if reqID == "" {
+			reqID = reqlog.NewRequestID()
+		}
+		w.Header().Set("X-Request-ID", reqID)
+
+		ctx, entry := reqlog.NewContext(r.Context(), reqID)
+		r = r.WithContext(ctx)
+
+		sw := &statusWriter{ResponseWriter: w, status: 200}
+		start := time.Now()
+		next.ServeHTTP(sw, r)
+		entry.Log(r.Method, r.URL.Path, sw.status, time.Since(start))
+	})
+}
+
+// withMetrics records request duration in metrics.RequestDuration, labeled
+// by route (the registered mux pattern, i.e. r.URL.Path since this router
+// has no path parameters), method, and response status.
+func withMetrics(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		sw := &statusWriter{ResponseWriter: w, status: 200}
 		start := time.Now()
 		next.ServeHTTP(sw, r)
-		dur := time.Since(start)
-		log.Printf("%s %s %d %s", r.Method, r.URL.Path, sw.status, dur)
+		metrics.RequestDuration.WithLabelValues(r.URL.Path, r.Method, strconv.Itoa(sw.status)).Observe(time.Since(start).Seconds())
 	})
 }
 
@@ -61,63 +95,216 @@ func withCORS(next http.Handler) http.Handler {
 	})
 }
 
-func withRateLimit(perMin int) func(http.Handler) http.Handler {
+// rateLimitBucketIdle is how long an (ip, routeClass) token bucket may sit
+// unused before its state is swept, so long-running processes don't
+// accumulate one bucket per client forever.
+const rateLimitBucketIdle = 10 * time.Minute
+
+// tokenBucket is one (clientIP, routeClass) pair's token-bucket state: it
+// refills continuously at ratePerSec up to burst, rather than resetting
+// in a fixed window, so a client can burst up to its full quota and then
+// settle into a steady rate instead of either being denied outright or
+// getting a whole new window's worth of budget at once.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// classLimiter is one route class's (e.g. "portfolio", "derivatives",
+// "default") token bucket, keyed per client IP.
+type classLimiter struct {
+	ratePerSec float64
+	burst      float64
+
+	mu          sync.Mutex
+	buckets     map[string]*tokenBucket
+	lastCleanup time.Time
+}
+
+func newClassLimiter(perMin int, burst int) *classLimiter {
 	if perMin <= 0 {
 		perMin = 120
 	}
-	lim := newLimiter(perMin)
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if !lim.Allow(clientIP(r)) {
-				w.WriteHeader(http.StatusTooManyRequests)
-				_, _ = w.Write([]byte(`{"error":"rate_limited"}`))
-				return
+	if burst <= 0 {
+		burst = perMin
+	}
+	return &classLimiter{
+		ratePerSec: float64(perMin) / 60,
+		burst:      float64(burst),
+		buckets:    make(map[string]*tokenBucket),
+	}
+}
+
+// allow reports whether ip may take one token from this class's bucket
+// right now. remaining is the whole tokens left afterward (or currently,
+// if denied), for X-RateLimit-Remaining. retryAfter is how long until the
+// next token is available, for Retry-After/X-RateLimit-Reset on denial.
+func (c *classLimiter) allow(ip string) (ok bool, remaining int, retryAfter time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.lastCleanup.IsZero() || now.Sub(c.lastCleanup) > rateLimitBucketIdle {
+		for key, b := range c.buckets {
+			if now.Sub(b.last) > rateLimitBucketIdle {
+				delete(c.buckets, key)
 			}
-			next.ServeHTTP(w, r)
-		})
+		}
+		c.lastCleanup = now
+	}
+
+	b, found := c.buckets[ip]
+	if !found {
+		b = &tokenBucket{tokens: c.burst, last: now}
+		c.buckets[ip] = b
+	} else {
+		elapsed := now.Sub(b.last).Seconds()
+		b.tokens = math.Min(c.burst, b.tokens+elapsed*c.ratePerSec)
+		b.last = now
 	}
+
+	if b.tokens < 1 {
+		deficit := 1 - b.tokens
+		return false, int(b.tokens), time.Duration(deficit / c.ratePerSec * float64(time.Second))
+	}
+	b.tokens--
+	return true, int(b.tokens), 0
 }
 
-type limiter struct {
-	mu       sync.Mutex
-	perMin   int
-	buckets  map[string]*bucket
-	lastCleanup time.Time
+// stats reports this class's configured quota and how many distinct
+// clients currently hold a bucket, for /debug/ratelimit.
+func (c *classLimiter) stats() classLimiterStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return classLimiterStats{
+		RatePerMin:    int(c.ratePerSec * 60),
+		Burst:         int(c.burst),
+		ActiveBuckets: len(c.buckets),
+	}
 }
 
-type bucket struct {
-	count int
-	reset time.Time
+type classLimiterStats struct {
+	RatePerMin    int `json:"rate_per_min"`
+	Burst         int `json:"burst"`
+	ActiveBuckets int `json:"active_buckets"`
 }
 
-func newLimiter(perMin int) *limiter {
-	return &limiter{perMin: perMin, buckets: make(map[string]*bucket)}
+// rateLimiter dispatches each request to the classLimiter for its
+// routeClass, so "portfolio", "derivatives", and "default" get
+// independent quotas instead of sharing one global bucket.
+type rateLimiter struct {
+	classes map[string]*classLimiter
 }
 
-func (l *limiter) Allow(ip string) bool {
-	l.mu.Lock()
-	defer l.mu.Unlock()
+func newRateLimiter(cfg config.Config) *rateLimiter {
+	return &rateLimiter{classes: map[string]*classLimiter{
+		"portfolio":   newClassLimiter(cfg.RateLimitPortfolioPerMin, cfg.RateLimitBurst),
+		"derivatives": newClassLimiter(cfg.RateLimitDerivativesPerMin, cfg.RateLimitBurst),
+		"default":     newClassLimiter(cfg.RateLimitPerMin, cfg.RateLimitBurst),
+	}}
+}
 
-	b, ok := l.buckets[ip]
-	now := time.Now()
-	if l.lastCleanup.IsZero() || now.Sub(l.lastCleanup) > time.Minute {
-		for key, bucket := range l.buckets {
-			if now.After(bucket.reset) {
-				delete(l.buckets, key)
-			}
+// routeClass maps a request path to the quota that governs it: portfolio
+// and derivatives each get their own (they're the routes that fan out to
+// the heaviest upstreams), everything else shares "default".
+func routeClass(path string) string {
+	switch {
+	case strings.HasPrefix(path, "/api/v1/portfolio"):
+		return "portfolio"
+	case strings.HasPrefix(path, "/api/v1/derivatives"):
+		return "derivatives"
+	default:
+		return "default"
+	}
+}
+
+func (l *rateLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		class := routeClass(r.URL.Path)
+		cl := l.classes[class]
+		ok, remaining, retryAfter := cl.allow(clientIP(r))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(retryAfter).Unix(), 10))
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":"rate_limited"}`))
+			return
 		}
-		l.lastCleanup = now
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *rateLimiter) stats() map[string]classLimiterStats {
+	out := make(map[string]classLimiterStats, len(l.classes))
+	for class, cl := range l.classes {
+		out[class] = cl.stats()
+	}
+	return out
+}
+
+// concurrencyLimiter is a global semaphore bounding how many requests the
+// process serves at once, independent of per-client rate limits, so a
+// backlog of slow upstream calls (Python, Binance, ...) can't pile up
+// enough in-flight goroutines to collapse the process.
+type concurrencyLimiter struct {
+	sem chan struct{}
+	max int
+}
+
+func newConcurrencyLimiter(max int) *concurrencyLimiter {
+	if max <= 0 {
+		max = 64
 	}
-	if !ok || now.After(b.reset) {
-		l.buckets[ip] = &bucket{count: 1, reset: now.Add(time.Minute)}
-		return true
+	return &concurrencyLimiter{sem: make(chan struct{}, max), max: max}
+}
+
+func (c *concurrencyLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case c.sem <- struct{}{}:
+			defer func() { <-c.sem }()
+			next.ServeHTTP(w, r)
+		default:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":"overloaded"}`))
+		}
+	})
+}
+
+// stats reports how many of the global concurrency slots are currently
+// held, for /debug/ratelimit.
+func (c *concurrencyLimiter) stats() (inUse int, max int) {
+	return len(c.sem), c.max
+}
+
+// debugRateLimitHandler reports every route class's quota and active
+// client count plus the global concurrency limiter's usage, so operators
+// can see why a client is being throttled without grepping logs.
+func debugRateLimitHandler(limiter *rateLimiter, concurrency *concurrencyLimiter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		inUse, max := concurrency.stats()
+		resp := map[string]any{
+			"routes": limiter.stats(),
+			"global_concurrency": map[string]int{
+				"in_use": inUse,
+				"max":    max,
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
 	}
+}
 
-	if b.count >= l.perMin {
-		return false
+// debugBreakerHandler reports every upstream path's circuit state and
+// trip/probe counts, so operators can see which endpoint is degraded
+// without grepping logs.
+func debugBreakerHandler(py *services.PythonClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(py.BreakerStats())
 	}
-	b.count++
-	return true
 }
 
 func clientIP(r *http.Request) string {
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"macroquant-intel/backend-go/internal/config"
+	"macroquant-intel/backend-go/internal/services"
+)
+
+func TestClassLimiterAllowsBurstThenThrottles(t *testing.T) {
+	cl := newClassLimiter(60, 2)
+
+	ok, remaining, _ := cl.allow("1.2.3.4")
+	if !ok || remaining != 1 {
+		t.Fatalf("expected first request allowed with 1 remaining, got ok=%v remaining=%d", ok, remaining)
+	}
+	ok, remaining, _ = cl.allow("1.2.3.4")
+	if !ok || remaining != 0 {
+		t.Fatalf("expected second request allowed with 0 remaining, got ok=%v remaining=%d", ok, remaining)
+	}
+	ok, _, retryAfter := cl.allow("1.2.3.4")
+	if ok {
+		t.Fatalf("expected third request to be throttled")
+	}
+	if retryAfter <= 0 {
+		t.Fatalf("expected a positive retryAfter, got %v", retryAfter)
+	}
+}
+
+func TestClassLimiterTracksClientsIndependently(t *testing.T) {
+	cl := newClassLimiter(60, 1)
+
+	if ok, _, _ := cl.allow("1.1.1.1"); !ok {
+		t.Fatalf("expected first client's request to be allowed")
+	}
+	if ok, _, _ := cl.allow("2.2.2.2"); !ok {
+		t.Fatalf("expected second client's request to be allowed despite first client exhausting its bucket")
+	}
+}
+
+func TestRouteClassMapsKnownPrefixes(t *testing.T) {
+	cases := map[string]string{
+		"/api/v1/portfolio":             "portfolio",
+		"/api/v1/portfolio/daily-brief": "portfolio",
+		"/api/v1/derivatives":           "derivatives",
+		"/api/v1/intel":                 "default",
+		"/metrics":                      "default",
+	}
+	for path, want := range cases {
+		if got := routeClass(path); got != want {
+			t.Errorf("routeClass(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestConcurrencyLimiterRejectsWhenFull(t *testing.T) {
+	cl := newConcurrencyLimiter(1)
+	cl.sem <- struct{}{}
+	defer func() { <-cl.sem }()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/v1/health", nil)
+	handlerCalled := false
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { handlerCalled = true })
+	cl.middleware(handler).ServeHTTP(rec, req)
+
+	if handlerCalled {
+		t.Fatalf("expected downstream handler not to run when the semaphore is full")
+	}
+	if rec.Code != 503 {
+		t.Fatalf("expected 503 when over capacity, got %d", rec.Code)
+	}
+}
+
+func TestDebugRateLimitEndpointReportsQuotasAndConcurrency(t *testing.T) {
+	cfg := config.Config{RequestTimeout: 1, RateLimitPerMin: 120, RateLimitBurst: 30, GlobalConcurrencyLimit: 64}
+	cache := services.NewMemoryCache(cfg)
+	defer cache.Close()
+	router := NewRouter(cfg, cache, services.NewPythonClient(cfg), services.NewQuotesClient(cfg, cache), services.NewSymbolRegistry("", 10), nil)
+
+	req := httptest.NewRequest("GET", "/debug/ratelimit", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != 200 {
+		t.Fatalf("expected 200 from /debug/ratelimit, got %d", rec.Code)
+	}
+	var body struct {
+		Routes            map[string]classLimiterStats `json:"routes"`
+		GlobalConcurrency map[string]int               `json:"global_concurrency"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response body: %v", err)
+	}
+	for _, class := range []string{"portfolio", "derivatives", "default"} {
+		if _, ok := body.Routes[class]; !ok {
+			t.Fatalf("expected %q in routes, got %+v", class, body.Routes)
+		}
+	}
+	if body.GlobalConcurrency["max"] != 64 {
+		t.Fatalf("expected global_concurrency max of 64, got %+v", body.GlobalConcurrency)
+	}
+}
@@ -3,13 +3,19 @@ package http
 import (
 	"net/http"
 
+	"macroquant-intel/backend-go/internal/alerts"
 	"macroquant-intel/backend-go/internal/config"
 	"macroquant-intel/backend-go/internal/handlers"
+	"macroquant-intel/backend-go/internal/metrics"
 	"macroquant-intel/backend-go/internal/services"
 )
 
-func NewRouter(cfg config.Config, cache services.Cache, py *services.PythonClient) http.Handler {
-	api := handlers.New(cfg, cache, py)
+func NewRouter(cfg config.Config, cache services.Cache, py *services.PythonClient, quotes *services.QuotesClient, symbols *services.SymbolRegistry, alertStore *alerts.Store) http.Handler {
+	api := handlers.New(cfg, cache, py, quotes, symbols, alertStore)
+
+	if coll, ok := cache.(metrics.Collector); ok {
+		metrics.RegisterCollector(coll)
+	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/v1/health", api.Health)
@@ -26,11 +32,24 @@ func NewRouter(cfg config.Config, cache services.Cache, py *services.PythonClien
 	mux.HandleFunc("/api/v1/forecasts/metrics", api.ForecastMetrics)
 	mux.HandleFunc("/api/v1/events/latest", api.EventsLatest)
 	mux.HandleFunc("/api/v1/stream", api.StreamIntel)
+	mux.HandleFunc("/api/v1/alerts/rules", api.AlertRules)
+	mux.HandleFunc("/api/v1/alerts/mutes", api.AlertMutes)
+	mux.HandleFunc("/api/v1/alerts/history", api.AlertHistory)
+	mux.HandleFunc("/symbols", api.Symbols)
+	mux.HandleFunc("/admin/symbols/reload", api.ReloadSymbols)
+	mux.Handle("/metrics", metrics.BasicAuth(cfg.MetricsBasicAuth, metrics.Handler()))
+
+	limiter := newRateLimiter(cfg)
+	concurrency := newConcurrencyLimiter(cfg.GlobalConcurrencyLimit)
+	mux.HandleFunc("/debug/ratelimit", debugRateLimitHandler(limiter, concurrency))
+	mux.HandleFunc("/debug/breaker", debugBreakerHandler(py))
 
 	h := http.Handler(mux)
 	h = withRecovery(h)
 	h = withLogging(h)
-	h = withRateLimit(cfg.RateLimitPerMin)(h)
+	h = withMetrics(h)
+	h = limiter.middleware(h)
+	h = concurrency.middleware(h)
 	h = withCORS(h)
 	return h
 }
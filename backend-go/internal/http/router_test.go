@@ -0,0 +1,60 @@
+package http
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"macroquant-intel/backend-go/internal/config"
+	"macroquant-intel/backend-go/internal/services"
+)
+
+func TestMetricsEndpointScrapesAfterRequests(t *testing.T) {
+	cfg := config.Config{RequestTimeout: 1}
+	cache := services.NewMemoryCache(cfg)
+	defer cache.Close()
+	router := NewRouter(cfg, cache, services.NewPythonClient(cfg), services.NewQuotesClient(cfg, cache), services.NewSymbolRegistry("", 10), nil)
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest("GET", "/api/v1/health", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+	}
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != 200 {
+		t.Fatalf("expected 200 from /metrics, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "http_request_duration_seconds") {
+		t.Fatalf("expected http_request_duration_seconds in scrape body, got:\n%s", body)
+	}
+	if !strings.Contains(body, "cache_entries") {
+		t.Fatalf("expected cache_entries in scrape body (MemoryCache collector), got:\n%s", body)
+	}
+}
+
+func TestMetricsEndpointRequiresBasicAuthWhenConfigured(t *testing.T) {
+	cfg := config.Config{RequestTimeout: 1, MetricsBasicAuth: "admin:secret"}
+	cache := services.NewMemoryCache(cfg)
+	defer cache.Close()
+	router := NewRouter(cfg, cache, services.NewPythonClient(cfg), services.NewQuotesClient(cfg, cache), services.NewSymbolRegistry("", 10), nil)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != 401 {
+		t.Fatalf("expected 401 without credentials, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/metrics", nil)
+	req.SetBasicAuth("admin", "secret")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != 200 {
+		t.Fatalf("expected 200 with valid credentials, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,153 @@
+// Package metrics holds the process-wide Prometheus collectors shared by
+// the HTTP middleware, upstream clients, and cache layer, plus the
+// /metrics handler that exposes them.
+package metrics
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// RequestDuration tracks HTTP handler latency by route, method, and
+// response status, recorded by withMetrics in internal/http.
+var RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "http_request_duration_seconds",
+	Help:    "HTTP request duration in seconds.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"route", "method", "status"})
+
+// UpstreamRequests counts calls to upstream services by client
+// (py/quotes/deriv/embeddings/bars) and outcome (ok, upstream_4xx, upstream_5xx, timeout,
+// network).
+var UpstreamRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "upstream_requests_total",
+	Help: "Upstream requests by client and outcome.",
+}, []string{"client", "outcome"})
+
+// UpstreamCalls counts calls admitted or rejected by services.Breaker, by
+// upstream key (e.g. "/portfolio") and the circuit state they were
+// evaluated in (closed, half_open, open). It is distinct from
+// UpstreamRequests, which counts completed calls by outcome rather than
+// breaker admission decisions.
+var UpstreamCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "upstream_calls_total",
+	Help: "Breaker-admitted or rejected upstream calls by key and circuit state.",
+}, []string{"key", "state"})
+
+// CacheEvents counts handler-level cache decisions by key prefix and event
+// (hit, miss, stale, down). This is distinct from the LRU-level
+// hit/miss/eviction counters a Cache implementation may register as its
+// own Collector (see services.MemoryCache): CacheEvents captures what a
+// handler decided to serve (e.g. a stale fallback while the upstream is
+// down), not just whether a key was found.
+var CacheEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "cache_events_total",
+	Help: "Handler-level cache decisions by key prefix and event.",
+}, []string{"key_prefix", "event"})
+
+// QuotesBackoffActive is 1 while QuotesClient is backing off the upstream
+// quotes provider after a failed fetch, 0 otherwise.
+var QuotesBackoffActive = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "quotes_backoff_active",
+	Help: "1 while the quotes client is backing off the upstream provider.",
+})
+
+// BreakerState reports each Breaker-guarded key's current circuit state as
+// a gauge (0=closed, 1=open, 2=half_open), so operators can see which
+// upstream endpoint is degraded without grepping logs.
+var BreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "breaker_state",
+	Help: "Current circuit state per Breaker key: 0=closed, 1=open, 2=half_open.",
+}, []string{"key"})
+
+// BreakerTrips counts how many times a Breaker key's circuit has opened,
+// whether from the closed state exceeding FailLimit or a half-open probe
+// failing.
+var BreakerTrips = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "breaker_trips_total",
+	Help: "Circuit opens per Breaker key.",
+}, []string{"key"})
+
+// BreakerProbes counts half-open probes admitted per Breaker key.
+var BreakerProbes = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "breaker_probes_total",
+	Help: "Half-open probes admitted per Breaker key.",
+}, []string{"key"})
+
+// CacheCoalesced counts Get calls by key prefix that joined an in-flight
+// fetch for the same key via singleflight instead of triggering their own,
+// so a traffic spike against one symbol doesn't amplify upstream load.
+var CacheCoalesced = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "cache_coalesced_total",
+	Help: "Fetches that joined an in-flight singleflight call instead of running their own.",
+}, []string{"key_prefix"})
+
+// BackgroundRefreshFailures counts async stale-while-revalidate refreshes
+// (triggered by CacheEvents' "stale" event) that failed, by key prefix.
+var BackgroundRefreshFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "cache_background_refresh_failures_total",
+	Help: "Background stale-while-revalidate refreshes that failed, by key prefix.",
+}, []string{"key_prefix"})
+
+// Collector is an alias for prometheus.Collector, so callers (e.g.
+// internal/http's router) can type-assert against it without importing
+// client_golang themselves.
+type Collector = prometheus.Collector
+
+// RegisterCollector registers c against the default registry scraped by
+// Handler. It tolerates c already being registered (e.g. NewRouter being
+// called more than once in tests) and is a no-op if c is nil.
+func RegisterCollector(c Collector) {
+	if c == nil {
+		return
+	}
+	err := prometheus.Register(c)
+	if err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if !errors.As(err, &already) {
+			panic(err)
+		}
+	}
+}
+
+// Handler returns the promhttp handler serving every collector registered
+// against the default registry (including the Go runtime and process
+// collectors client_golang registers by default).
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// BasicAuth wraps next so requests must present the given
+// username/password over HTTP Basic Auth. It is a no-op if creds is empty
+// or not in "user:pass" form.
+func BasicAuth(creds string, next http.Handler) http.Handler {
+	if creds == "" {
+		return next
+	}
+	user, pass, ok := splitCreds(creds)
+	if !ok {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqUser, reqPass, ok := r.BasicAuth()
+		if !ok || reqUser != user || reqPass != pass {
+			w.Header().Set("WWW-Authenticate", `Basic realm="metrics"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func splitCreds(creds string) (user string, pass string, ok bool) {
+	for i := 0; i < len(creds); i++ {
+		if creds[i] == ':' {
+			return creds[:i], creds[i+1:], true
+		}
+	}
+	return "", "", false
+}
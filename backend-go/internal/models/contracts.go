@@ -1,5 +1,17 @@
 package models
 
+import "encoding/json"
+
+// IntelEvent is one line of a streamed `/intel/run?stream=1` response: a
+// progress update, a log line, a heartbeat, or (on the final event) the
+// full IntelResponse, distinguished by Type. Data is left raw since each
+// Type has its own shape and most callers only care about a subset of
+// them.
+type IntelEvent struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
 type IntelRequest struct {
 	Timeframe    string   `json:"timeframe"`
 	NewsTimespan string   `json:"newsTimespan"`
@@ -7,95 +19,95 @@ type IntelRequest struct {
 }
 
 type IntelResponse struct {
-	TsISO        string           `json:"tsISO"`
-	Timeframe    string           `json:"timeframe"`
-	NewsTimespan string           `json:"newsTimespan"`
-	Market       MarketSnapshot   `json:"market"`
-	Leaders      []LeadersGroup   `json:"leaders"`
-	TopNews      []NewsItem       `json:"top_news"`
-	EventFeed    EventFeed        `json:"event_feed"`
-	Flow         FlowPanel        `json:"flow"`
-	Derivatives  DerivativesPanel `json:"derivatives"`
-	Risk         RiskPanel        `json:"risk"`
-	Debug        DebugInfo        `json:"debug"`
-	DailyMovers  DailyEquityMovers `json:"daily_equity_movers"`
-	Forecast     ForecastPanel     `json:"forecast"`
-	Etag         string            `json:"etag"`
-	BlockHashes  map[string]string `json:"block_hashes"`
-	ChangedBlocks []string         `json:"changed_blocks"`
+	TsISO         string            `json:"tsISO"`
+	Timeframe     string            `json:"timeframe"`
+	NewsTimespan  string            `json:"newsTimespan"`
+	Market        MarketSnapshot    `json:"market"`
+	Leaders       []LeadersGroup    `json:"leaders"`
+	TopNews       []NewsItem        `json:"top_news"`
+	EventFeed     EventFeed         `json:"event_feed"`
+	Flow          FlowPanel         `json:"flow"`
+	Derivatives   DerivativesPanel  `json:"derivatives"`
+	Risk          RiskPanel         `json:"risk"`
+	Debug         DebugInfo         `json:"debug"`
+	DailyMovers   DailyEquityMovers `json:"daily_equity_movers"`
+	Forecast      ForecastPanel     `json:"forecast"`
+	Etag          string            `json:"etag"`
+	BlockHashes   map[string]string `json:"block_hashes"`
+	ChangedBlocks []string          `json:"changed_blocks"`
 }
 
 type MarketSnapshot struct {
-	TsISO     string            `json:"tsISO"`
-	CoinGecko CoinGeckoSnapshot `json:"coingecko"`
-	Yahoo     YahooSnapshot     `json:"yahoo"`
-	SnapshotMeta map[string]any `json:"snapshot_meta,omitempty"`
+	TsISO        string            `json:"tsISO"`
+	CoinGecko    CoinGeckoSnapshot `json:"coingecko"`
+	Yahoo        YahooSnapshot     `json:"yahoo"`
+	SnapshotMeta map[string]any    `json:"snapshot_meta,omitempty"`
 }
 
 type CoinGeckoSnapshot struct {
-	BtcPriceUsd  float64            `json:"btc_price_usd"`
-	EthPriceUsd  float64            `json:"eth_price_usd"`
-	BtcChg24     float64            `json:"btc_chg_24h"`
-	EthChg24     float64            `json:"eth_chg_24h"`
-	TotalVolUsd  float64            `json:"total_vol_usd"`
-	TotalMcapUsd float64            `json:"total_mcap_usd"`
-	Dominance    map[string]float64 `json:"dominance"`
-	Deltas       map[string]float64 `json:"deltas"`
-	AltcoinTotalValueExBtcUsd *float64 `json:"altcoin_total_value_ex_btc_usd"`
-	AltcoinTotalValueExBtcSource *string `json:"altcoin_total_value_ex_btc_source"`
-	AltcoinTotalValueExBtcTsUtc *string `json:"altcoin_total_value_ex_btc_ts_utc"`
-	AltcoinTotalValueExBtcTsISO *string `json:"altcoin_total_value_ex_btc_tsISO"`
+	BtcPriceUsd                  float64            `json:"btc_price_usd"`
+	EthPriceUsd                  float64            `json:"eth_price_usd"`
+	BtcChg24                     float64            `json:"btc_chg_24h"`
+	EthChg24                     float64            `json:"eth_chg_24h"`
+	TotalVolUsd                  float64            `json:"total_vol_usd"`
+	TotalMcapUsd                 float64            `json:"total_mcap_usd"`
+	Dominance                    map[string]float64 `json:"dominance"`
+	Deltas                       map[string]float64 `json:"deltas"`
+	AltcoinTotalValueExBtcUsd    *float64           `json:"altcoin_total_value_ex_btc_usd"`
+	AltcoinTotalValueExBtcSource *string            `json:"altcoin_total_value_ex_btc_source"`
+	AltcoinTotalValueExBtcTsUtc  *string            `json:"altcoin_total_value_ex_btc_ts_utc"`
+	AltcoinTotalValueExBtcTsISO  *string            `json:"altcoin_total_value_ex_btc_tsISO"`
 }
 
 type YahooSnapshot struct {
-	DXY      float64 `json:"dxy"`
-	QQQ      float64 `json:"qqq"`
-	Nasdaq   float64 `json:"nasdaq"`
-	FTSE     float64 `json:"ftse"`
-	EuroStoxx float64 `json:"eurostoxx"`
-	Oil      float64 `json:"oil"`
-	Gold     float64 `json:"gold"`
-	Silver   float64 `json:"silver"`
-	Copper   float64 `json:"copper"`
-	Bist     float64 `json:"bist"`
-	VIX      float64 `json:"vix"`
-	Btc      float64 `json:"btc"`
-	Eth      float64 `json:"eth"`
-	BtcChg24 float64 `json:"btc_chg_24h"`
-	EthChg24 float64 `json:"eth_chg_24h"`
-	DXYChg24 float64 `json:"dxy_chg_24h"`
-	QQQChg24 float64 `json:"qqq_chg_24h"`
-	NasdaqChg24 float64 `json:"nasdaq_chg_24h"`
-	FTSEChg24 float64 `json:"ftse_chg_24h"`
+	DXY            float64 `json:"dxy"`
+	QQQ            float64 `json:"qqq"`
+	Nasdaq         float64 `json:"nasdaq"`
+	FTSE           float64 `json:"ftse"`
+	EuroStoxx      float64 `json:"eurostoxx"`
+	Oil            float64 `json:"oil"`
+	Gold           float64 `json:"gold"`
+	Silver         float64 `json:"silver"`
+	Copper         float64 `json:"copper"`
+	Bist           float64 `json:"bist"`
+	VIX            float64 `json:"vix"`
+	Btc            float64 `json:"btc"`
+	Eth            float64 `json:"eth"`
+	BtcChg24       float64 `json:"btc_chg_24h"`
+	EthChg24       float64 `json:"eth_chg_24h"`
+	DXYChg24       float64 `json:"dxy_chg_24h"`
+	QQQChg24       float64 `json:"qqq_chg_24h"`
+	NasdaqChg24    float64 `json:"nasdaq_chg_24h"`
+	FTSEChg24      float64 `json:"ftse_chg_24h"`
 	EuroStoxxChg24 float64 `json:"eurostoxx_chg_24h"`
-	OilChg24 float64 `json:"oil_chg_24h"`
-	GoldChg24 float64 `json:"gold_chg_24h"`
-	SilverChg24 float64 `json:"silver_chg_24h"`
-	CopperChg24 float64 `json:"copper_chg_24h"`
-	BistChg24 float64 `json:"bist_chg_24h"`
+	OilChg24       float64 `json:"oil_chg_24h"`
+	GoldChg24      float64 `json:"gold_chg_24h"`
+	SilverChg24    float64 `json:"silver_chg_24h"`
+	CopperChg24    float64 `json:"copper_chg_24h"`
+	BistChg24      float64 `json:"bist_chg_24h"`
 }
 
 type NewsItem struct {
-	Title                      string   `json:"title"`
-	URL                        string   `json:"url"`
-	Source                     string   `json:"source"`
-	PublishedAtISO             string   `json:"publishedAtISO"`
-	Tags                       []string `json:"tags"`
-	Score                      int      `json:"score"`
-	TierScore                  int      `json:"tier_score"`
-	Ts                         string   `json:"ts"`
-	Category                   string   `json:"category"`
-	Entities                   []string `json:"entities"`
-	EventType                  string   `json:"event_type"`
-	ImpactChannel              []string `json:"impact_channel"`
-	AssetClassBias             []string `json:"asset_class_bias"`
-	ExpectedDirectionShortTerm string   `json:"expected_direction_short_term"`
-	RelevanceScore             int      `json:"relevance_score"`
-	QualityScore               int      `json:"quality_score"`
-	DedupClusterID             string   `json:"dedup_cluster_id"`
-	OtherSources               []string `json:"other_sources"`
-	ShortSummary               string   `json:"short_summary"`
-	ImpactPotential            int      `json:"impact_potential"`
+	Title                      string       `json:"title"`
+	URL                        string       `json:"url"`
+	Source                     string       `json:"source"`
+	PublishedAtISO             string       `json:"publishedAtISO"`
+	Tags                       []string     `json:"tags"`
+	Score                      int          `json:"score"`
+	TierScore                  int          `json:"tier_score"`
+	Ts                         string       `json:"ts"`
+	Category                   string       `json:"category"`
+	Entities                   []string     `json:"entities"`
+	EventType                  string       `json:"event_type"`
+	ImpactChannel              []string     `json:"impact_channel"`
+	AssetClassBias             []string     `json:"asset_class_bias"`
+	ExpectedDirectionShortTerm string       `json:"expected_direction_short_term"`
+	RelevanceScore             int          `json:"relevance_score"`
+	QualityScore               int          `json:"quality_score"`
+	DedupClusterID             string       `json:"dedup_cluster_id"`
+	OtherSources               []string     `json:"other_sources"`
+	ShortSummary               string       `json:"short_summary"`
+	ImpactPotential            int          `json:"impact_potential"`
 	PersonEvent                *PersonEvent `json:"person_event"`
 }
 
@@ -221,21 +233,21 @@ type FlowPanel struct {
 }
 
 type EventPoint struct {
-	ID           string  `json:"id"`
-	Title        string  `json:"title"`
-	TsISO        string  `json:"tsISO"`
-	Timeframe    string  `json:"timeframe"`
-	VolumeZ      float64 `json:"volume_z"`
-	PrePostRatio float64 `json:"pre_post_ratio"`
-	PriceMovePct float64 `json:"price_move_pct"`
-	EventID      string  `json:"event_id"`
-	Headline     string  `json:"headline"`
-	PublishedAtUTC string `json:"published_at_utc"`
-	PublishedAtTSI string `json:"published_at_tsi"`
-	Scope        string  `json:"scope"`
-	Sectors      []string `json:"sectors"`
-	Reactions    map[string]EventReactions `json:"reactions"`
-	Combined     *CombinedReaction `json:"combined"`
+	ID             string                    `json:"id"`
+	Title          string                    `json:"title"`
+	TsISO          string                    `json:"tsISO"`
+	Timeframe      string                    `json:"timeframe"`
+	VolumeZ        float64                   `json:"volume_z"`
+	PrePostRatio   float64                   `json:"pre_post_ratio"`
+	PriceMovePct   float64                   `json:"price_move_pct"`
+	EventID        string                    `json:"event_id"`
+	Headline       string                    `json:"headline"`
+	PublishedAtUTC string                    `json:"published_at_utc"`
+	PublishedAtTSI string                    `json:"published_at_tsi"`
+	Scope          string                    `json:"scope"`
+	Sectors        []string                  `json:"sectors"`
+	Reactions      map[string]EventReactions `json:"reactions"`
+	Combined       *CombinedReaction         `json:"combined"`
 }
 
 type ReactionWindow struct {
@@ -244,16 +256,16 @@ type ReactionWindow struct {
 }
 
 type EventReactions struct {
-	Pre       *ReactionWindow         `json:"pre"`
-	Post      map[string]ReactionWindow `json:"post"`
-	Around    map[string]ReactionWindow `json:"around"`
-	Pre30mRet *float64               `json:"pre_30m_ret"`
-	Post30mRet *float64              `json:"post_30m_ret"`
-	VolumeZ   float64                 `json:"volume_z"`
-	SparkPre  []float64               `json:"spark_pre"`
-	SparkPost []float64               `json:"spark_post"`
-	DataStatus string                `json:"data_status"`
-	MissingFields []string           `json:"missing_fields"`
+	Pre           *ReactionWindow           `json:"pre"`
+	Post          map[string]ReactionWindow `json:"post"`
+	Around        map[string]ReactionWindow `json:"around"`
+	Pre30mRet     *float64                  `json:"pre_30m_ret"`
+	Post30mRet    *float64                  `json:"post_30m_ret"`
+	VolumeZ       float64                   `json:"volume_z"`
+	SparkPre      []float64                 `json:"spark_pre"`
+	SparkPost     []float64                 `json:"spark_post"`
+	DataStatus    string                    `json:"data_status"`
+	MissingFields []string                  `json:"missing_fields"`
 }
 
 type CombinedReaction struct {
@@ -269,16 +281,16 @@ type DerivativesPanel struct {
 }
 
 type RiskPanel struct {
-	Flags    []string `json:"flags"`
-	RSI      float64  `json:"rsi"`
-	FundingZ float64  `json:"funding_z"`
-	OIDelta  float64  `json:"oi_delta"`
+	Flags     []string `json:"flags"`
+	RSI       float64  `json:"rsi"`
+	FundingZ  float64  `json:"funding_z"`
+	OIDelta   float64  `json:"oi_delta"`
 	FearGreed *float64 `json:"fear_greed,omitempty"`
 }
 
 type DebugInfo struct {
-	DataMissing []string `json:"data_missing"`
-	Notes       []string `json:"notes"`
+	DataMissing            []string        `json:"data_missing"`
+	Notes                  []string        `json:"notes"`
 	ProviderMetricsSummary string          `json:"provider_metrics_summary"`
 	Providers              []ProviderDebug `json:"providers"`
 }
@@ -295,10 +307,10 @@ type ProviderDebug struct {
 }
 
 type DailyEquityMoverEvidence struct {
-	EventID  string `json:"event_id"`
-	Category string `json:"category"`
-	Relevance int   `json:"relevance"`
-	Quality  int    `json:"quality"`
+	EventID   string `json:"event_id"`
+	Category  string `json:"category"`
+	Relevance int    `json:"relevance"`
+	Quality   int    `json:"quality"`
 }
 
 type DailyEquityMoverItem struct {
@@ -332,11 +344,11 @@ type DailyEquityMovers struct {
 }
 
 type CryptoOutlook struct {
-	Asof        string   `json:"asof"`
-	BtcBias     int      `json:"btc_bias"`
-	EthBias     int      `json:"eth_bias"`
-	Confidence  int      `json:"confidence"`
-	Drivers     []string `json:"drivers"`
+	Asof         string   `json:"asof"`
+	BtcBias      int      `json:"btc_bias"`
+	EthBias      int      `json:"eth_bias"`
+	Confidence   int      `json:"confidence"`
+	Drivers      []string `json:"drivers"`
 	WatchMetrics []string `json:"watch_metrics"`
 }
 
@@ -355,8 +367,9 @@ type DerivativesSeries struct {
 }
 
 type DerivativesComputed struct {
-	FundingZ   float64 `json:"funding_z"`
-	OIDeltaPct float64 `json:"oi_delta_pct"`
+	FundingZ          float64 `json:"funding_z"`
+	FundingZClassical float64 `json:"funding_z_classical"`
+	OIDeltaPct        float64 `json:"oi_delta_pct"`
 }
 
 type DerivativesHealth struct {
@@ -377,23 +390,59 @@ type DerivativesResponse struct {
 	Health   DerivativesHealth   `json:"health"`
 }
 
+// DerivativesVenueHealth is one venue's health entry inside a
+// DerivativesAggregated response, analogous to DerivativesHealth but
+// scoped to a single exchange within the fan-out.
+type DerivativesVenueHealth struct {
+	Exchange     string `json:"exchange"`
+	LatencyMs    int64  `json:"latency_ms"`
+	DegradedMode bool   `json:"degraded_mode"`
+	RateLimited  bool   `json:"rate_limited"`
+	Dropped      bool   `json:"dropped,omitempty"`
+	CoolOffUntil string `json:"cool_off_until,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// DerivativesComposite is the cross-venue view of DerivativesAggregated,
+// combining every reachable venue's funding rate and open interest
+// according to Weighting.
+type DerivativesComposite struct {
+	Weighting    string  `json:"weighting"`
+	FundingRate  float64 `json:"funding_rate"`
+	FundingZ     float64 `json:"funding_z"`
+	OpenInterest float64 `json:"open_interest"`
+}
+
+// DerivativesAggregated is the exchange=all response: per-venue series
+// and health alongside a single weighted composite view.
+type DerivativesAggregated struct {
+	Ts        string                            `json:"ts"`
+	Symbol    string                            `json:"symbol"`
+	Venues    map[string]DerivativesResponse    `json:"venues"`
+	Health    map[string]DerivativesVenueHealth `json:"health"`
+	Composite DerivativesComposite              `json:"composite"`
+}
+
 // External API responses
 
 type HealthResponse struct {
-	Ok          bool              `json:"ok"`
-	TsISO       string            `json:"tsISO"`
-	Service     string            `json:"service"`
-	Version     string            `json:"version,omitempty"`
-	Deps        []string          `json:"deps"`
+	Ok          bool                 `json:"ok"`
+	TsISO       string               `json:"tsISO"`
+	Service     string               `json:"service"`
+	Version     string               `json:"version,omitempty"`
+	Deps        []string             `json:"deps"`
 	DepsStatus  map[string]DepStatus `json:"deps_status,omitempty"`
-	DataMissing []string          `json:"data_missing"`
-	Env         map[string]bool   `json:"env"`
-	Features    map[string]bool   `json:"features"`
+	Degraded    bool                 `json:"degraded,omitempty"`
+	DataMissing []string             `json:"data_missing"`
+	Env         map[string]bool      `json:"env"`
+	Features    map[string]bool      `json:"features"`
 }
 
 type DepStatus struct {
-	Ok    bool   `json:"ok"`
-	Error string `json:"error,omitempty"`
+	Ok           bool   `json:"ok"`
+	Error        string `json:"error,omitempty"`
+	LatencyMs    int    `json:"latency_ms,omitempty"`
+	LastGoodAgeS int    `json:"last_good_age_s,omitempty"`
 }
 
 type NewsPageResponse struct {
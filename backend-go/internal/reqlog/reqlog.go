@@ -0,0 +1,107 @@
+// Package reqlog provides request-scoped structured logging: a request ID
+// generated (or accepted from an incoming header) at the edge, threaded
+// through context.Context, and a per-request log of upstream call attempts
+// that's emitted as one JSON record when the request completes.
+package reqlog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Logger is the process-wide structured logger. It's a package var rather
+// than threaded through every constructor because it has no per-request or
+// per-service state, matching how the metrics package exposes its
+// collectors as package-level globals.
+var Logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+// NewRequestID mints a request ID using the same crypto/rand hex convention
+// as the alerts/intel service ID generators, with a UnixNano fallback if
+// crypto/rand is unavailable.
+func NewRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("req-%d", time.Now().UnixNano())
+	}
+	return "req-" + hex.EncodeToString(b)
+}
+
+// UpstreamAttempt records one attempt at an upstream call, for the
+// "upstreams" array in the per-request log record.
+type UpstreamAttempt struct {
+	URL         string `json:"url"`
+	Attempt     int    `json:"attempt"`
+	Status      int    `json:"status,omitempty"`
+	RateLimited bool   `json:"rate_limited,omitempty"`
+	WaitMs      int64  `json:"wait_ms,omitempty"`
+	Err         string `json:"err,omitempty"`
+}
+
+// Entry accumulates a single request's upstream attempts and their total
+// backoff wait, for Log to emit once the request finishes.
+type Entry struct {
+	ID string
+
+	mu           sync.Mutex
+	upstreams    []UpstreamAttempt
+	backoffTotal time.Duration
+}
+
+type ctxKey struct{}
+
+// NewContext returns a context carrying a fresh Entry for id, and the Entry
+// itself so the caller (withLogging) can Log it once the request completes.
+func NewContext(ctx context.Context, id string) (context.Context, *Entry) {
+	e := &Entry{ID: id}
+	return context.WithValue(ctx, ctxKey{}, e), e
+}
+
+// FromContext returns the Entry attached by NewContext, or nil if ctx has
+// none (e.g. in tests that construct a plain context.Background()).
+func FromContext(ctx context.Context) *Entry {
+	e, _ := ctx.Value(ctxKey{}).(*Entry)
+	return e
+}
+
+// RecordUpstream appends a to e's upstream log and folds its wait into the
+// running backoff total. It's a no-op on a nil Entry, so callers can record
+// unconditionally without checking FromContext's result first.
+func (e *Entry) RecordUpstream(a UpstreamAttempt) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.upstreams = append(e.upstreams, a)
+	e.backoffTotal += time.Duration(a.WaitMs) * time.Millisecond
+}
+
+// Log emits one JSON record for the request: method, path, status, total
+// duration, every recorded upstream attempt, and the total time spent
+// backing off between them. It's a no-op on a nil Entry.
+func (e *Entry) Log(method, path string, status int, dur time.Duration) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	upstreams := make([]UpstreamAttempt, len(e.upstreams))
+	copy(upstreams, e.upstreams)
+	backoffTotal := e.backoffTotal
+	e.mu.Unlock()
+
+	Logger.Info("request",
+		"request_id", e.ID,
+		"method", method,
+		"path", path,
+		"status", status,
+		"duration_ms", dur.Milliseconds(),
+		"upstreams", upstreams,
+		"backoff_total_ms", backoffTotal.Milliseconds(),
+	)
+}
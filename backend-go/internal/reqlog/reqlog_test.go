@@ -0,0 +1,43 @@
+package reqlog
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRecordUpstreamAccumulatesBackoffTotal(t *testing.T) {
+	_, entry := NewContext(context.Background(), "req-test")
+	entry.RecordUpstream(UpstreamAttempt{URL: "https://a", Attempt: 1, Status: 429, RateLimited: true, WaitMs: 1000})
+	entry.RecordUpstream(UpstreamAttempt{URL: "https://a", Attempt: 2, Status: 200})
+
+	if len(entry.upstreams) != 2 {
+		t.Fatalf("expected 2 recorded upstream attempts, got %d", len(entry.upstreams))
+	}
+	if entry.backoffTotal != 1*time.Second {
+		t.Fatalf("expected backoff total of 1s, got %v", entry.backoffTotal)
+	}
+}
+
+func TestFromContextReturnsNilWithoutAnEntry(t *testing.T) {
+	if e := FromContext(context.Background()); e != nil {
+		t.Fatalf("expected a nil Entry for a context with none attached, got %+v", e)
+	}
+}
+
+func TestRecordUpstreamIsNoOpOnNilEntry(t *testing.T) {
+	var e *Entry
+	e.RecordUpstream(UpstreamAttempt{URL: "https://a"})
+	e.Log("GET", "/x", 200, time.Millisecond)
+}
+
+func TestNewRequestIDProducesDistinctIDs(t *testing.T) {
+	a := NewRequestID()
+	b := NewRequestID()
+	if a == "" || b == "" {
+		t.Fatalf("expected non-empty request IDs, got %q and %q", a, b)
+	}
+	if a == b {
+		t.Fatalf("expected two calls to NewRequestID to differ, both returned %q", a)
+	}
+}
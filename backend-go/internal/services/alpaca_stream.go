@@ -0,0 +1,267 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"macroquant-intel/backend-go/internal/config"
+)
+
+const (
+	alpacaReconnectMin = 1 * time.Second
+	alpacaReconnectMax = 30 * time.Second
+)
+
+// alpacaStream maintains a single WebSocket connection to Alpaca's
+// real-time market data feed and pushes trade/quote updates straight into
+// QuotesClient's in-memory cache, so Fetch can serve sub-second-fresh
+// prices without round-tripping through the Python upstream. It is purely
+// additive: QuotesClient falls back to its existing HTTP fetch path
+// whenever the socket is disconnected or a requested symbol isn't
+// subscribed yet.
+type alpacaStream struct {
+	cfg     config.Config
+	onQuote func(symbol string, price float64, tsISO string)
+
+	mu         sync.Mutex
+	connected  bool
+	subscribed map[string]struct{}
+	pending    map[string]struct{}
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// newAlpacaStream starts the reconnect supervisor in the background and
+// returns immediately. onQuote is invoked from the supervisor goroutine
+// whenever a trade or quote frame updates a symbol's price.
+func newAlpacaStream(cfg config.Config, onQuote func(symbol string, price float64, tsISO string)) *alpacaStream {
+	s := &alpacaStream{
+		cfg:        cfg,
+		onQuote:    onQuote,
+		subscribed: make(map[string]struct{}),
+		pending:    make(map[string]struct{}),
+		stopCh:     make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.supervise()
+	return s
+}
+
+// ensureSubscribed queues any symbols not already subscribed so the live
+// connection (or the next reconnect) picks them up. It never blocks on the
+// network, so it's safe to call from Fetch on every request.
+func (s *alpacaStream) ensureSubscribed(symbols []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sym := range symbols {
+		if _, ok := s.subscribed[sym]; ok {
+			continue
+		}
+		s.pending[sym] = struct{}{}
+	}
+}
+
+// isFresh reports whether symbol is live-subscribed on a connected socket,
+// meaning the stream (rather than the HTTP fallback) is the authoritative
+// source for it right now.
+func (s *alpacaStream) isFresh(symbol string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.connected {
+		return false
+	}
+	_, ok := s.subscribed[symbol]
+	return ok
+}
+
+// Close stops the supervisor and waits for the current connection, if any,
+// to tear down. Safe to call more than once.
+func (s *alpacaStream) Close() error {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+	s.wg.Wait()
+	return nil
+}
+
+// supervise reconnects with exponential backoff (capped at
+// alpacaReconnectMax, jittered to avoid a thundering herd against Alpaca)
+// for as long as the stream hasn't been closed.
+func (s *alpacaStream) supervise() {
+	defer s.wg.Done()
+	backoff := alpacaReconnectMin
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		default:
+		}
+
+		connectedOK := s.runOnce()
+
+		s.mu.Lock()
+		s.connected = false
+		s.mu.Unlock()
+
+		if connectedOK {
+			backoff = alpacaReconnectMin
+		}
+
+		wait := backoff + time.Duration(rand.Int63n(int64(backoff)/2+1))
+		select {
+		case <-s.stopCh:
+			return
+		case <-time.After(wait):
+		}
+		backoff *= 2
+		if backoff > alpacaReconnectMax {
+			backoff = alpacaReconnectMax
+		}
+	}
+}
+
+// runOnce dials the socket, performs the auth/subscribe handshake, and
+// pumps frames until the connection drops or the stream is closed. It
+// returns whether the handshake completed, so the caller knows to reset
+// its backoff.
+func (s *alpacaStream) runOnce() (connectedOK bool) {
+	feed := s.cfg.AlpacaFeed
+	if feed == "" {
+		feed = "iex"
+	}
+	url := fmt.Sprintf("wss://stream.data.alpaca.markets/v2/%s", feed)
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	if err := conn.WriteJSON(map[string]string{
+		"action": "auth",
+		"key":    s.cfg.AlpacaAPIKey,
+		"secret": s.cfg.AlpacaAPISecret,
+	}); err != nil {
+		return false
+	}
+	if err := s.resubscribeAll(conn); err != nil {
+		return false
+	}
+
+	s.mu.Lock()
+	s.connected = true
+	s.mu.Unlock()
+	connectedOK = true
+
+	closed := make(chan struct{})
+	go func() {
+		select {
+		case <-s.stopCh:
+			_ = conn.Close()
+		case <-closed:
+		}
+	}()
+	defer close(closed)
+
+	for {
+		if err := s.drainPending(conn); err != nil {
+			return connectedOK
+		}
+		var frames []json.RawMessage
+		if err := conn.ReadJSON(&frames); err != nil {
+			return connectedOK
+		}
+		for _, raw := range frames {
+			s.handleFrame(raw)
+		}
+	}
+}
+
+// resubscribeAll sends a subscribe message covering every symbol already
+// known (pending + previously subscribed), used right after a reconnect
+// since Alpaca doesn't remember a prior connection's subscriptions.
+func (s *alpacaStream) resubscribeAll(conn *websocket.Conn) error {
+	s.mu.Lock()
+	all := make([]string, 0, len(s.subscribed)+len(s.pending))
+	for sym := range s.subscribed {
+		all = append(all, sym)
+	}
+	for sym := range s.pending {
+		all = append(all, sym)
+		s.subscribed[sym] = struct{}{}
+		delete(s.pending, sym)
+	}
+	s.mu.Unlock()
+	if len(all) == 0 {
+		return nil
+	}
+	return conn.WriteJSON(map[string]any{
+		"action": "subscribe",
+		"trades": all,
+		"quotes": all,
+	})
+}
+
+// drainPending subscribes any symbols queued by ensureSubscribed since the
+// connection was opened or last drained.
+func (s *alpacaStream) drainPending(conn *websocket.Conn) error {
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	add := make([]string, 0, len(s.pending))
+	for sym := range s.pending {
+		add = append(add, sym)
+		s.subscribed[sym] = struct{}{}
+		delete(s.pending, sym)
+	}
+	s.mu.Unlock()
+	return conn.WriteJSON(map[string]any{
+		"action": "subscribe",
+		"trades": add,
+		"quotes": add,
+	})
+}
+
+// alpacaFrame covers the fields used across Alpaca's trade ("t"), quote
+// ("q"), success, and error frame types; unused fields are left zero.
+type alpacaFrame struct {
+	Type   string  `json:"T"`
+	Symbol string  `json:"S"`
+	Price  float64 `json:"p"`
+	Bid    float64 `json:"bp"`
+	Ask    float64 `json:"ap"`
+	TimeMs int64   `json:"t"`
+}
+
+func (s *alpacaStream) handleFrame(raw json.RawMessage) {
+	var f alpacaFrame
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return
+	}
+	var price float64
+	switch f.Type {
+	case "t":
+		price = f.Price
+	case "q":
+		if f.Bid > 0 && f.Ask > 0 {
+			price = (f.Bid + f.Ask) / 2
+		} else {
+			price = f.Ask
+			if price == 0 {
+				price = f.Bid
+			}
+		}
+	default:
+		return
+	}
+	if f.Symbol == "" || price == 0 {
+		return
+	}
+	s.onQuote(f.Symbol, price, time.Now().UTC().Format(time.RFC3339))
+}
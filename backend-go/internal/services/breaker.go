@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"macroquant-intel/backend-go/internal/config"
+	"macroquant-intel/backend-go/internal/metrics"
+)
+
+// ErrCircuitOpen is returned by Breaker-guarded calls when the circuit for
+// the upstream key is open, so the caller fails fast instead of waiting on
+// a request that a circuit breaker has already decided is likely to fail.
+var ErrCircuitOpen = errors.New("services: upstream circuit open")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// breakerEntry is one upstream key's circuit state and bulkhead semaphore.
+// cooldown is this key's own current cooldown, starting at the Breaker's
+// baseline and doubling (capped at maxCooldown) each time a half-open
+// probe fails, so a key that keeps flapping backs off further instead of
+// retrying every baseline interval forever.
+type breakerEntry struct {
+	mu            sync.Mutex
+	state         breakerState
+	failures      int
+	openedAt      time.Time
+	probeInFlight bool
+	cooldown      time.Duration
+	trips         int64
+	probes        int64
+
+	inflight chan struct{}
+}
+
+// Breaker is a keyed circuit-breaker plus bulkhead: every upstream path
+// (e.g. "/portfolio", "/intel") gets its own three-state machine (closed
+// -> open after FailLimit consecutive failures -> half-open single probe
+// once its cooldown has elapsed -> closed again on a successful probe, or
+// open again with a doubled cooldown on a failed one) and its own bounded
+// concurrency semaphore, so one slow or failing upstream path can't starve
+// the others sharing a PythonClient. Stats exposes each key's state and
+// trip/probe counts for a debug endpoint, so operators can see which
+// upstream endpoint is degraded rather than the whole Python service being
+// marked down.
+type Breaker struct {
+	failLimit   int
+	cooldown    time.Duration
+	maxCooldown time.Duration
+	maxInflight int
+
+	mu      sync.Mutex
+	entries map[string]*breakerEntry
+}
+
+// NewBreaker builds a Breaker from cfg's circuit-breaker and bulkhead
+// settings. Keys are created lazily on first use, so callers don't need to
+// register upstream paths up front.
+func NewBreaker(cfg config.Config) *Breaker {
+	maxCooldown := cfg.CircuitCooldownMax
+	if maxCooldown <= 0 || maxCooldown < cfg.CircuitCooldown {
+		maxCooldown = cfg.CircuitCooldown
+	}
+	return &Breaker{
+		failLimit:   cfg.CircuitFailLimit,
+		cooldown:    cfg.CircuitCooldown,
+		maxCooldown: maxCooldown,
+		maxInflight: cfg.MaxInflightPerUpstream,
+		entries:     make(map[string]*breakerEntry),
+	}
+}
+
+func (b *Breaker) entry(key string) *breakerEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e, ok := b.entries[key]
+	if !ok {
+		max := b.maxInflight
+		if max <= 0 {
+			max = 1
+		}
+		e = &breakerEntry{inflight: make(chan struct{}, max), cooldown: b.cooldown}
+		b.entries[key] = e
+	}
+	return e
+}
+
+// Acquire blocks until a bulkhead slot for key is free or ctx is done. The
+// returned release func must be called exactly once to free the slot.
+func (b *Breaker) Acquire(ctx context.Context, key string) (release func(), err error) {
+	e := b.entry(key)
+	select {
+	case e.inflight <- struct{}{}:
+		return func() { <-e.inflight }, nil
+	case <-ctx.Done():
+		return func() {}, ctx.Err()
+	}
+}
+
+// TryAcquire acquires a bulkhead slot for key without blocking, reporting
+// ok=false if none is free. Used by request hedging (see
+// FetchJSONWithStatusHedged), which must be able to decline a hedge
+// attempt rather than either blocking or exceeding the per-key cap by
+// firing its second request outside the bulkhead entirely.
+func (b *Breaker) TryAcquire(key string) (release func(), ok bool) {
+	e := b.entry(key)
+	select {
+	case e.inflight <- struct{}{}:
+		return func() { <-e.inflight }, true
+	default:
+		return nil, false
+	}
+}
+
+// Allow reports whether a call against key may proceed given its current
+// circuit state, flipping an open circuit to half-open once its cooldown
+// has elapsed and admitting exactly one probe at a time in that state.
+// Every call is recorded against upstream_calls_total under the state it
+// was evaluated in, and breaker_state is kept in sync with the state
+// returned.
+func (b *Breaker) Allow(key string) bool {
+	e := b.entry(key)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.state == breakerOpen {
+		if time.Since(e.openedAt) < e.cooldown {
+			metrics.UpstreamCalls.WithLabelValues(key, breakerOpen.String()).Inc()
+			return false
+		}
+		e.state = breakerHalfOpen
+		e.probeInFlight = false
+		metrics.BreakerState.WithLabelValues(key).Set(float64(breakerHalfOpen))
+	}
+
+	if e.state == breakerHalfOpen {
+		if e.probeInFlight {
+			metrics.UpstreamCalls.WithLabelValues(key, breakerHalfOpen.String()).Inc()
+			return false
+		}
+		e.probeInFlight = true
+		e.probes++
+		metrics.BreakerProbes.WithLabelValues(key).Inc()
+		metrics.UpstreamCalls.WithLabelValues(key, breakerHalfOpen.String()).Inc()
+		return true
+	}
+
+	metrics.UpstreamCalls.WithLabelValues(key, breakerClosed.String()).Inc()
+	return true
+}
+
+// Success records a call against key succeeding, closing its circuit and
+// resetting its cooldown back to the Breaker's baseline.
+func (b *Breaker) Success(key string) {
+	e := b.entry(key)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.state = breakerClosed
+	e.failures = 0
+	e.openedAt = time.Time{}
+	e.probeInFlight = false
+	e.cooldown = b.cooldown
+	metrics.BreakerState.WithLabelValues(key).Set(float64(breakerClosed))
+}
+
+// Fail records a call against key failing. A failed half-open probe
+// reopens the circuit immediately and doubles its cooldown (capped at
+// maxCooldown), so a key that keeps flapping waits longer between probes
+// each time; in the closed state the circuit opens once FailLimit
+// consecutive failures have been seen, at the baseline cooldown.
+func (b *Breaker) Fail(key string) {
+	e := b.entry(key)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.probeInFlight = false
+	if e.state == breakerHalfOpen {
+		e.state = breakerOpen
+		e.openedAt = time.Now()
+		e.cooldown *= 2
+		if e.cooldown > b.maxCooldown {
+			e.cooldown = b.maxCooldown
+		}
+		e.trips++
+		metrics.BreakerTrips.WithLabelValues(key).Inc()
+		metrics.BreakerState.WithLabelValues(key).Set(float64(breakerOpen))
+		return
+	}
+	e.failures++
+	if e.failures >= b.failLimit {
+		e.state = breakerOpen
+		e.openedAt = time.Now()
+		e.trips++
+		metrics.BreakerTrips.WithLabelValues(key).Inc()
+		metrics.BreakerState.WithLabelValues(key).Set(float64(breakerOpen))
+	}
+}
+
+// BreakerKeyStats is one key's point-in-time circuit state, for Stats.
+type BreakerKeyStats struct {
+	State    string `json:"state"`
+	Failures int    `json:"failures"`
+	Trips    int64  `json:"trips"`
+	Probes   int64  `json:"probes"`
+}
+
+// Stats returns a snapshot of every key the Breaker has seen, so a debug
+// endpoint can show operators which upstream path is degraded rather than
+// just "the Python service is down".
+func (b *Breaker) Stats() map[string]BreakerKeyStats {
+	b.mu.Lock()
+	keys := make([]string, 0, len(b.entries))
+	entries := make([]*breakerEntry, 0, len(b.entries))
+	for key, e := range b.entries {
+		keys = append(keys, key)
+		entries = append(entries, e)
+	}
+	b.mu.Unlock()
+
+	out := make(map[string]BreakerKeyStats, len(keys))
+	for i, key := range keys {
+		e := entries[i]
+		e.mu.Lock()
+		out[key] = BreakerKeyStats{
+			State:    e.state.String(),
+			Failures: e.failures,
+			Trips:    e.trips,
+			Probes:   e.probes,
+		}
+		e.mu.Unlock()
+	}
+	return out
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"macroquant-intel/backend-go/internal/config"
+)
+
+func newTestBreaker(failLimit int, cooldown time.Duration, maxInflight int) *Breaker {
+	return NewBreaker(config.Config{
+		CircuitFailLimit:       failLimit,
+		CircuitCooldown:        cooldown,
+		MaxInflightPerUpstream: maxInflight,
+	})
+}
+
+func TestBreakerOpensAfterFailLimitAndRejects(t *testing.T) {
+	b := newTestBreaker(2, time.Minute, 4)
+
+	if !b.Allow("/news") {
+		t.Fatal("expected first call to be allowed while closed")
+	}
+	b.Fail("/news")
+	if !b.Allow("/news") {
+		t.Fatal("expected call to be allowed before fail limit is reached")
+	}
+	b.Fail("/news")
+
+	if b.Allow("/news") {
+		t.Fatal("expected circuit to reject once fail limit was reached")
+	}
+}
+
+func TestBreakerHalfOpensAfterCooldownAndClosesOnSuccess(t *testing.T) {
+	b := newTestBreaker(1, 10*time.Millisecond, 4)
+
+	b.Fail("/portfolio")
+	if b.Allow("/portfolio") {
+		t.Fatal("expected circuit open immediately after tripping")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if !b.Allow("/portfolio") {
+		t.Fatal("expected a single half-open probe to be admitted after cooldown")
+	}
+	if b.Allow("/portfolio") {
+		t.Fatal("expected a second concurrent probe to be rejected while one is in flight")
+	}
+
+	b.Success("/portfolio")
+	if !b.Allow("/portfolio") {
+		t.Fatal("expected circuit closed after a successful probe")
+	}
+}
+
+func TestBreakerFailedProbeReopensCircuit(t *testing.T) {
+	b := newTestBreaker(1, 10*time.Millisecond, 4)
+
+	b.Fail("/portfolio")
+	time.Sleep(20 * time.Millisecond)
+	if !b.Allow("/portfolio") {
+		t.Fatal("expected probe to be admitted")
+	}
+	b.Fail("/portfolio")
+
+	if b.Allow("/portfolio") {
+		t.Fatal("expected circuit to reopen after a failed probe")
+	}
+}
+
+func TestBreakerKeysAreIndependent(t *testing.T) {
+	b := newTestBreaker(1, time.Minute, 4)
+
+	b.Fail("/news")
+	if b.Allow("/news") {
+		t.Fatal("expected /news circuit to be open")
+	}
+	if !b.Allow("/portfolio") {
+		t.Fatal("expected /portfolio circuit to be unaffected by /news failures")
+	}
+}
+
+func TestBreakerDoublesCooldownOnRepeatedHalfOpenFailure(t *testing.T) {
+	b := NewBreaker(config.Config{
+		CircuitFailLimit:       1,
+		CircuitCooldown:        10 * time.Millisecond,
+		CircuitCooldownMax:     30 * time.Millisecond,
+		MaxInflightPerUpstream: 4,
+	})
+
+	b.Fail("/intel") // opens at the 10ms baseline
+	time.Sleep(15 * time.Millisecond)
+	if !b.Allow("/intel") {
+		t.Fatal("expected the first probe to be admitted after the baseline cooldown")
+	}
+	b.Fail("/intel") // failed probe: cooldown doubles to 20ms
+
+	time.Sleep(15 * time.Millisecond)
+	if b.Allow("/intel") {
+		t.Fatal("expected the doubled cooldown to still be in effect at 15ms")
+	}
+	time.Sleep(10 * time.Millisecond)
+	if !b.Allow("/intel") {
+		t.Fatal("expected the doubled cooldown to have elapsed by 25ms")
+	}
+}
+
+func TestBreakerSuccessResetsCooldownToBaseline(t *testing.T) {
+	b := NewBreaker(config.Config{
+		CircuitFailLimit:       2,
+		CircuitCooldown:        10 * time.Millisecond,
+		CircuitCooldownMax:     40 * time.Millisecond,
+		MaxInflightPerUpstream: 4,
+	})
+
+	b.Fail("/portfolio")
+	b.Fail("/portfolio")
+	time.Sleep(15 * time.Millisecond)
+	b.Allow("/portfolio") // admits the probe
+	b.Fail("/portfolio")  // failed probe: cooldown doubles to 20ms
+	time.Sleep(25 * time.Millisecond)
+	b.Allow("/portfolio") // admits the second probe
+	b.Success("/portfolio")
+
+	b.Fail("/portfolio") // back to closed after Success, so this is just the first failure
+	if !b.Allow("/portfolio") {
+		t.Fatal("expected a single failure after Success to not reopen the circuit")
+	}
+	b.Fail("/portfolio") // second failure reopens the circuit
+	time.Sleep(15 * time.Millisecond)
+	if !b.Allow("/portfolio") {
+		t.Fatal("expected the cooldown to be back at the 10ms baseline after Success, not the doubled 20ms")
+	}
+}
+
+func TestBreakerStatsReportsStateAndCounts(t *testing.T) {
+	b := newTestBreaker(1, time.Minute, 4)
+
+	b.Allow("/intel")
+	b.Fail("/intel")
+
+	stats := b.Stats()
+	got, ok := stats["/intel"]
+	if !ok {
+		t.Fatal("expected Stats to report the /intel key")
+	}
+	if got.State != "open" || got.Trips != 1 {
+		t.Fatalf("expected state=open trips=1, got %+v", got)
+	}
+}
+
+func TestBreakerAcquireBlocksUntilSlotFreedOrContextDone(t *testing.T) {
+	b := newTestBreaker(5, time.Minute, 1)
+
+	release, err := b.Acquire(context.Background(), "/intel")
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := b.Acquire(ctx, "/intel"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline exceeded while slot is held, got %v", err)
+	}
+
+	release()
+	release2, err := b.Acquire(context.Background(), "/intel")
+	if err != nil {
+		t.Fatalf("acquire after release: %v", err)
+	}
+	release2()
+}
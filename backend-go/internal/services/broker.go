@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Broker is a sibling to Cache for cross-instance coordination: pub/sub
+// fan-out plus a leader lease so only one replica does expensive work on
+// behalf of a shared cache key. It's optional — callers type-assert a
+// Cache to Broker and fall back to per-replica behavior when absent, which
+// is what the in-process MemoryCache path does.
+type Broker interface {
+	Publish(ctx context.Context, channel string, payload []byte) error
+	Subscribe(ctx context.Context, channel string) (<-chan []byte, func())
+	// AcquireOrRenewLease grants the lease for key to owner if it is free
+	// or already held by owner, extending it to ttl either way. It returns
+	// false if another owner currently holds it.
+	AcquireOrRenewLease(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error)
+}
+
+var leaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+func (r *RedisCache) Publish(ctx context.Context, channel string, payload []byte) error {
+	return r.client.Publish(ctx, channel, payload).Err()
+}
+
+func (r *RedisCache) Subscribe(ctx context.Context, channel string) (<-chan []byte, func()) {
+	sub := r.client.Subscribe(ctx, channel)
+	out := make(chan []byte, 8)
+
+	go func() {
+		defer close(out)
+		for msg := range sub.Channel() {
+			select {
+			case out <- []byte(msg.Payload):
+			default:
+			}
+		}
+	}()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() { _ = sub.Close() })
+	}
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+	}()
+
+	return out, unsubscribe
+}
+
+func (r *RedisCache) AcquireOrRenewLease(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	res, err := leaseScript.Run(ctx, r.client, []string{key}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
@@ -0,0 +1,170 @@
+// Package broker provides a minimal pub/sub abstraction used to fan intel
+// snapshots out across backend replicas. New selects a NATS-backed
+// implementation when NATS_URL is configured, falling back to an
+// in-process implementation so single-node deploys and tests still work
+// without a NATS server.
+package broker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+
+	"macroquant-intel/backend-go/internal/config"
+)
+
+// Broker fans byte payloads out to subscribers by subject. Every
+// implementation caches the last payload published to each subject, so a
+// client that subscribes after the fact is delivered the current value
+// immediately instead of waiting for the next publish.
+type Broker interface {
+	Publish(ctx context.Context, subject string, payload []byte) error
+	// Subscribe delivers the cached last value for subject (if any) and
+	// every payload published to it from then on, until ctx is canceled
+	// or the returned cancel func is called.
+	Subscribe(ctx context.Context, subject string) (ch <-chan []byte, cancel func())
+	// Healthy reports whether the broker's backing transport, if any, is
+	// reachable. The in-process broker is always healthy.
+	Healthy(ctx context.Context) error
+}
+
+// New selects a Broker based on cfg.NATSURL: NATS when set and reachable,
+// otherwise an in-process broker.
+func New(cfg config.Config) Broker {
+	if cfg.NATSURL == "" {
+		return newInProcess()
+	}
+	nc, err := nats.Connect(cfg.NATSURL)
+	if err != nil {
+		return newInProcess()
+	}
+	return &natsBroker{conn: nc}
+}
+
+// inProcess fans payloads out to local subscriber channels only. It's the
+// default when NATS isn't configured, and what tests use.
+type inProcess struct {
+	mu   sync.Mutex
+	last map[string][]byte
+	subs map[string]map[chan []byte]struct{}
+}
+
+func newInProcess() *inProcess {
+	return &inProcess{
+		last: make(map[string][]byte),
+		subs: make(map[string]map[chan []byte]struct{}),
+	}
+}
+
+func (b *inProcess) Publish(_ context.Context, subject string, payload []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.last[subject] = payload
+	for ch := range b.subs[subject] {
+		select {
+		case ch <- payload:
+		default:
+		}
+	}
+	return nil
+}
+
+func (b *inProcess) Subscribe(ctx context.Context, subject string) (<-chan []byte, func()) {
+	out := make(chan []byte, 1)
+
+	b.mu.Lock()
+	if b.subs[subject] == nil {
+		b.subs[subject] = make(map[chan []byte]struct{})
+	}
+	b.subs[subject][out] = struct{}{}
+	if last, ok := b.last[subject]; ok {
+		select {
+		case out <- last:
+		default:
+		}
+	}
+	b.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs[subject], out)
+			if len(b.subs[subject]) == 0 {
+				delete(b.subs, subject)
+			}
+			b.mu.Unlock()
+			close(out)
+		})
+	}
+	go func() {
+		<-ctx.Done()
+		cancel()
+	}()
+	return out, cancel
+}
+
+func (b *inProcess) Healthy(context.Context) error { return nil }
+
+// natsBroker fans payloads out via a NATS server, so multiple backend
+// replicas share a single producer per subject.
+type natsBroker struct {
+	conn *nats.Conn
+
+	mu   sync.Mutex
+	last map[string][]byte
+}
+
+func (b *natsBroker) Publish(_ context.Context, subject string, payload []byte) error {
+	b.mu.Lock()
+	if b.last == nil {
+		b.last = make(map[string][]byte)
+	}
+	b.last[subject] = payload
+	b.mu.Unlock()
+	return b.conn.Publish(subject, payload)
+}
+
+func (b *natsBroker) Subscribe(ctx context.Context, subject string) (<-chan []byte, func()) {
+	out := make(chan []byte, 1)
+	sub, err := b.conn.Subscribe(subject, func(msg *nats.Msg) {
+		select {
+		case out <- msg.Data:
+		default:
+		}
+	})
+	if err == nil {
+		b.mu.Lock()
+		if last, ok := b.last[subject]; ok {
+			select {
+			case out <- last:
+			default:
+			}
+		}
+		b.mu.Unlock()
+	}
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			if sub != nil {
+				_ = sub.Unsubscribe()
+			}
+			close(out)
+		})
+	}
+	go func() {
+		<-ctx.Done()
+		cancel()
+	}()
+	return out, cancel
+}
+
+func (b *natsBroker) Healthy(context.Context) error {
+	if !b.conn.IsConnected() {
+		return fmt.Errorf("nats: not connected")
+	}
+	return nil
+}
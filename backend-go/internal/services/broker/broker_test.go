@@ -0,0 +1,67 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"macroquant-intel/backend-go/internal/config"
+)
+
+func TestInProcessBrokerDeliversLastValueToNewSubscriber(t *testing.T) {
+	b := New(config.Config{})
+	ctx := context.Background()
+
+	if err := b.Publish(ctx, "intel.v1.test", []byte("first")); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	ch, cancel := b.Subscribe(ctx, "intel.v1.test")
+	defer cancel()
+
+	select {
+	case got := <-ch:
+		if string(got) != "first" {
+			t.Fatalf("expected last value %q, got %q", "first", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for cached last value")
+	}
+}
+
+func TestInProcessBrokerFansOutToMultipleSubscribers(t *testing.T) {
+	b := New(config.Config{})
+	ctx := context.Background()
+
+	chA, cancelA := b.Subscribe(ctx, "intel.v1.fanout")
+	defer cancelA()
+	chB, cancelB := b.Subscribe(ctx, "intel.v1.fanout")
+	defer cancelB()
+
+	if err := b.Publish(ctx, "intel.v1.fanout", []byte("tick")); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	for _, ch := range []<-chan []byte{chA, chB} {
+		select {
+		case got := <-ch:
+			if string(got) != "tick" {
+				t.Fatalf("expected %q, got %q", "tick", got)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for fan-out delivery")
+		}
+	}
+}
+
+func TestInProcessBrokerCancelStopsDelivery(t *testing.T) {
+	b := New(config.Config{})
+	ctx := context.Background()
+
+	ch, cancel := b.Subscribe(ctx, "intel.v1.cancel")
+	cancel()
+
+	if _, ok := <-ch; ok {
+		t.Fatal("expected channel to be closed after cancel")
+	}
+}
@@ -1,11 +1,17 @@
 package services
 
 import (
+	"container/list"
 	"context"
 	"encoding/json"
+	"errors"
+	"net"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
 
 	"macroquant-intel/backend-go/internal/config"
@@ -14,42 +20,209 @@ import (
 type Cache interface {
 	Get(ctx context.Context, key string) ([]byte, bool)
 	Set(ctx context.Context, key string, val []byte, ttl time.Duration) error
+	Del(ctx context.Context, key string) error
+}
+
+// Pinger is implemented by Cache backends with a remote dependency to
+// check, so Health can report it under DepsStatus. MemoryCache and
+// nearCache with no Redis behind them don't implement it.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// CacheStatsProvider is implemented by Cache backends that track hit/miss
+// accounting in-process. MemoryCache implements it; RedisCache does not,
+// since its counters live in Redis itself.
+type CacheStatsProvider interface {
+	Stats() CacheStats
 }
 
 type RedisCache struct {
 	client *redis.Client
+	prefix string
+	health *HealthRegistry
+}
+
+func (r *RedisCache) prefixedKey(key string) string {
+	return r.prefix + key
+}
+
+// SetHealthRegistry attaches h so Get/Set record their outcomes under the
+// "redis" source. Optional: a RedisCache with no registry attached just
+// skips recording.
+func (r *RedisCache) SetHealthRegistry(h *HealthRegistry) {
+	r.health = h
 }
 
+// Ping reports whether the Redis connection is reachable, for Health's
+// DepsStatus.
+func (r *RedisCache) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+const (
+	defaultMemoryCacheMaxEntries    = 10000
+	defaultMemoryCacheSweepInterval = 30 * time.Second
+)
+
+// MemoryCache is an in-process LRU cache with per-key TTLs. Entries are
+// bounded by maxEntries (evicting the least-recently-used key once full)
+// and swept for expiry by a background janitor goroutine, so keys that
+// are written and never read again (common for one-off Derivatives,
+// Leaders, and per-watchlist Intel lookups) don't accumulate forever.
+// Hit/miss/eviction counts are tracked overall and per key prefix via
+// Stats, for the /metrics handler.
 type MemoryCache struct {
-	mu    sync.Mutex
-	items map[string]memItem
+	mu         sync.Mutex
+	items      map[string]*list.Element
+	order      *list.List
+	maxEntries int
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+
+	stats prefixStats
+}
+
+type memEntry struct {
+	key string
+	val []byte
+	exp time.Time
+}
+
+// CacheStats is a point-in-time snapshot of a MemoryCache's counters,
+// overall and broken down by key prefix (the part of the key before the
+// first ":", e.g. "intel", "leaders", "derivatives").
+type CacheStats struct {
+	Entries   int
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	ByPrefix  map[string]PrefixStats
+}
+
+// PrefixStats holds the hit/miss/eviction counters for a single key prefix.
+type PrefixStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+}
+
+// prefixStats is the mutable, concurrency-safe counter store backing
+// CacheStats. Overall counters use atomics; per-prefix counters live
+// under a mutex since the prefix set is open-ended.
+type prefixStats struct {
+	hits, misses, evictions int64
+
+	mu       sync.Mutex
+	byPrefix map[string]*prefixCounter
 }
 
-type memItem struct {
-	val   []byte
-	exp   time.Time
+type prefixCounter struct {
+	hits, misses, evictions int64
 }
 
+func newPrefixStats() prefixStats {
+	return prefixStats{byPrefix: make(map[string]*prefixCounter)}
+}
+
+func (s *prefixStats) counter(key string) *prefixCounter {
+	prefix := keyPrefix(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.byPrefix[prefix]
+	if !ok {
+		c = &prefixCounter{}
+		s.byPrefix[prefix] = c
+	}
+	return c
+}
+
+func (s *prefixStats) hit(key string) {
+	atomic.AddInt64(&s.hits, 1)
+	atomic.AddInt64(&s.counter(key).hits, 1)
+}
+
+func (s *prefixStats) miss(key string) {
+	atomic.AddInt64(&s.misses, 1)
+	atomic.AddInt64(&s.counter(key).misses, 1)
+}
+
+func (s *prefixStats) evict(key string) {
+	atomic.AddInt64(&s.evictions, 1)
+	atomic.AddInt64(&s.counter(key).evictions, 1)
+}
+
+func (s *prefixStats) snapshot() (hits, misses, evictions int64, byPrefix map[string]PrefixStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	byPrefix = make(map[string]PrefixStats, len(s.byPrefix))
+	for prefix, c := range s.byPrefix {
+		byPrefix[prefix] = PrefixStats{
+			Hits:      atomic.LoadInt64(&c.hits),
+			Misses:    atomic.LoadInt64(&c.misses),
+			Evictions: atomic.LoadInt64(&c.evictions),
+		}
+	}
+	return atomic.LoadInt64(&s.hits), atomic.LoadInt64(&s.misses), atomic.LoadInt64(&s.evictions), byPrefix
+}
+
+func keyPrefix(key string) string {
+	if i := strings.IndexByte(key, ':'); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// NewCache builds the cache backend: a Redis-backed cache wrapped in a
+// small in-process near-cache when cfg.RedisURL resolves and is reachable,
+// falling back to a bare MemoryCache otherwise (single-node deploys,
+// tests, or Redis being briefly down at startup).
 func NewCache(cfg config.Config) Cache {
 	opt, err := redis.ParseURL(cfg.RedisURL)
 	if err != nil {
-		return NewMemoryCache()
+		return NewMemoryCache(cfg)
 	}
 	client := redis.NewClient(opt)
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 	if err := client.Ping(ctx).Err(); err != nil {
-		return NewMemoryCache()
+		return NewMemoryCache(cfg)
 	}
-	return &RedisCache{client: client}
+	redisCache := &RedisCache{client: client, prefix: cfg.RedisKeyPrefix}
+	return newNearCache(cfg, redisCache)
 }
 
-func NewMemoryCache() *MemoryCache {
-	return &MemoryCache{items: make(map[string]memItem)}
+// NewMemoryCache builds an LRU+TTL cache bounded by
+// cfg.MemoryCacheMaxEntries and starts its janitor goroutine, which wakes
+// every cfg.MemoryCacheSweepInterval to drop expired entries. Call Close
+// to stop the janitor once the cache is no longer needed.
+func NewMemoryCache(cfg config.Config) *MemoryCache {
+	maxEntries := cfg.MemoryCacheMaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultMemoryCacheMaxEntries
+	}
+	sweep := cfg.MemoryCacheSweepInterval
+	if sweep <= 0 {
+		sweep = defaultMemoryCacheSweepInterval
+	}
+	m := &MemoryCache{
+		items:      make(map[string]*list.Element),
+		order:      list.New(),
+		maxEntries: maxEntries,
+		stopCh:     make(chan struct{}),
+		stats:      newPrefixStats(),
+	}
+	m.wg.Add(1)
+	go m.janitor(sweep)
+	return m
 }
 
 func (r *RedisCache) Get(ctx context.Context, key string) ([]byte, bool) {
-	b, err := r.client.Get(ctx, key).Bytes()
+	start := time.Now()
+	b, err := r.client.Get(ctx, r.prefixedKey(key)).Bytes()
+	r.recordResult(start, err, err == redis.Nil)
 	if err != nil {
 		return nil, false
 	}
@@ -57,21 +230,61 @@ func (r *RedisCache) Get(ctx context.Context, key string) ([]byte, bool) {
 }
 
 func (r *RedisCache) Set(ctx context.Context, key string, val []byte, ttl time.Duration) error {
-	return r.client.Set(ctx, key, val, ttl).Err()
+	start := time.Now()
+	err := r.client.Set(ctx, r.prefixedKey(key), val, ttl).Err()
+	r.recordResult(start, err, false)
+	return err
+}
+
+// recordResult reports a Get/Set outcome to the attached HealthRegistry, if
+// any. A cache miss (redis.Nil) is a successful round-trip, not a failure.
+func (r *RedisCache) recordResult(start time.Time, err error, miss bool) {
+	if r.health == nil {
+		return
+	}
+	result := ProviderResult{Latency: time.Since(start)}
+	if err == nil || miss {
+		result.Ok = true
+	} else {
+		result.ErrorClass = redisErrorClass(err)
+		result.ErrorMsg = err.Error()
+	}
+	r.health.Record("redis", result)
+}
+
+// redisErrorClass classifies a Redis client error for HealthRegistry.
+// Redis has no notion of HTTP status codes, so every non-timeout failure
+// is bucketed as a 5xx-equivalent (the dependency itself is unhealthy,
+// not the request).
+func redisErrorClass(err error) string {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorClassTimeout
+	}
+	return ErrorClass5xx
+}
+
+func (r *RedisCache) Del(ctx context.Context, key string) error {
+	return r.client.Del(ctx, r.prefixedKey(key)).Err()
 }
 
 func (m *MemoryCache) Get(_ context.Context, key string) ([]byte, bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	it, ok := m.items[key]
+	el, ok := m.items[key]
 	if !ok {
+		m.stats.miss(key)
 		return nil, false
 	}
-	if !it.exp.IsZero() && time.Now().After(it.exp) {
-		delete(m.items, key)
+	entry := el.Value.(*memEntry)
+	if !entry.exp.IsZero() && time.Now().After(entry.exp) {
+		m.removeElement(el)
+		m.stats.miss(key)
 		return nil, false
 	}
-	return it.val, true
+	m.order.MoveToFront(el)
+	m.stats.hit(key)
+	return entry.val, true
 }
 
 func (m *MemoryCache) Set(_ context.Context, key string, val []byte, ttl time.Duration) error {
@@ -81,10 +294,212 @@ func (m *MemoryCache) Set(_ context.Context, key string, val []byte, ttl time.Du
 	if ttl > 0 {
 		exp = time.Now().Add(ttl)
 	}
-	m.items[key] = memItem{val: val, exp: exp}
+	if el, ok := m.items[key]; ok {
+		entry := el.Value.(*memEntry)
+		entry.val = val
+		entry.exp = exp
+		m.order.MoveToFront(el)
+		return nil
+	}
+	el := m.order.PushFront(&memEntry{key: key, val: val, exp: exp})
+	m.items[key] = el
+	for m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		if oldest == nil {
+			break
+		}
+		m.stats.evict(oldest.Value.(*memEntry).key)
+		m.removeElement(oldest)
+	}
 	return nil
 }
 
+func (m *MemoryCache) Del(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.items[key]; ok {
+		m.removeElement(el)
+	}
+	return nil
+}
+
+// removeElement detaches el from both the LRU list and the key map.
+// Callers must hold m.mu.
+func (m *MemoryCache) removeElement(el *list.Element) {
+	m.order.Remove(el)
+	delete(m.items, el.Value.(*memEntry).key)
+}
+
+// janitor periodically sweeps expired entries so keys that are written and
+// never read again don't linger past their TTL.
+func (m *MemoryCache) janitor(interval time.Duration) {
+	defer m.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.sweepExpired()
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+func (m *MemoryCache) sweepExpired() {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for el := m.order.Back(); el != nil; {
+		prev := el.Prev()
+		entry := el.Value.(*memEntry)
+		if !entry.exp.IsZero() && now.After(entry.exp) {
+			m.stats.evict(entry.key)
+			m.removeElement(el)
+		}
+		el = prev
+	}
+}
+
+// Close stops the janitor goroutine. Safe to call more than once.
+func (m *MemoryCache) Close() error {
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+	})
+	m.wg.Wait()
+	return nil
+}
+
+// Stats returns a snapshot of the cache's hit/miss/eviction counters,
+// overall and broken down by key prefix.
+func (m *MemoryCache) Stats() CacheStats {
+	m.mu.Lock()
+	entries := m.order.Len()
+	m.mu.Unlock()
+
+	hits, misses, evictions, byPrefix := m.stats.snapshot()
+	return CacheStats{
+		Entries:   entries,
+		Hits:      hits,
+		Misses:    misses,
+		Evictions: evictions,
+		ByPrefix:  byPrefix,
+	}
+}
+
+var (
+	cacheEntriesDesc   = prometheus.NewDesc("cache_entries", "Entries currently held in the in-process cache.", nil, nil)
+	cacheHitsDesc      = prometheus.NewDesc("cache_hits_total", "Total in-process cache hits.", []string{"prefix"}, nil)
+	cacheMissesDesc    = prometheus.NewDesc("cache_misses_total", "Total in-process cache misses.", []string{"prefix"}, nil)
+	cacheEvictionsDesc = prometheus.NewDesc("cache_evictions_total", "Total in-process cache evictions.", []string{"prefix"}, nil)
+)
+
+// Describe and Collect implement prometheus.Collector, so NewRouter can
+// register a MemoryCache directly against the /metrics endpoint.
+func (m *MemoryCache) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheEntriesDesc
+	ch <- cacheHitsDesc
+	ch <- cacheMissesDesc
+	ch <- cacheEvictionsDesc
+}
+
+func (m *MemoryCache) Collect(ch chan<- prometheus.Metric) {
+	stats := m.Stats()
+	ch <- prometheus.MustNewConstMetric(cacheEntriesDesc, prometheus.GaugeValue, float64(stats.Entries))
+	for prefix, p := range stats.ByPrefix {
+		ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, float64(p.Hits), prefix)
+		ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, float64(p.Misses), prefix)
+		ch <- prometheus.MustNewConstMetric(cacheEvictionsDesc, prometheus.CounterValue, float64(p.Evictions), prefix)
+	}
+}
+
+// nearCacheTTL bounds how long a near-cache entry is trusted before it's
+// re-fetched from Redis, independent of the underlying key's real TTL, so
+// a value changed by another replica isn't served stale for too long.
+const nearCacheTTL = 5 * time.Second
+
+// nearCache sits a small bounded MemoryCache in front of a RedisCache, so
+// hot keys (e.g. market:v1) are served from memory instead of round-
+// tripping to Redis on every request. Redis remains the source of truth:
+// Set and Del always write through, and local only ever holds a short-
+// lived copy.
+type nearCache struct {
+	local  *MemoryCache
+	remote *RedisCache
+}
+
+func newNearCache(cfg config.Config, remote *RedisCache) *nearCache {
+	localCfg := cfg
+	localCfg.MemoryCacheMaxEntries = cfg.NearCacheMaxEntries
+	return &nearCache{
+		local:  NewMemoryCache(localCfg),
+		remote: remote,
+	}
+}
+
+func (n *nearCache) Get(ctx context.Context, key string) ([]byte, bool) {
+	if b, ok := n.local.Get(ctx, key); ok {
+		return b, true
+	}
+	b, ok := n.remote.Get(ctx, key)
+	if !ok {
+		return nil, false
+	}
+	_ = n.local.Set(ctx, key, b, nearCacheTTL)
+	return b, true
+}
+
+func (n *nearCache) Set(ctx context.Context, key string, val []byte, ttl time.Duration) error {
+	if err := n.remote.Set(ctx, key, val, ttl); err != nil {
+		return err
+	}
+	localTTL := ttl
+	if localTTL <= 0 || localTTL > nearCacheTTL {
+		localTTL = nearCacheTTL
+	}
+	return n.local.Set(ctx, key, val, localTTL)
+}
+
+func (n *nearCache) Del(ctx context.Context, key string) error {
+	_ = n.local.Del(ctx, key)
+	return n.remote.Del(ctx, key)
+}
+
+// Ping reports whether the Redis connection behind the near-cache is
+// reachable, for Health's DepsStatus.
+func (n *nearCache) Ping(ctx context.Context) error {
+	return n.remote.Ping(ctx)
+}
+
+// SetHealthRegistry attaches h to the underlying RedisCache, so recorded
+// latency/errors reflect the real Redis round-trip and not just near-cache
+// hits.
+func (n *nearCache) SetHealthRegistry(h *HealthRegistry) {
+	n.remote.SetHealthRegistry(h)
+}
+
+// Stats reports the near-cache layer's own hit/miss/eviction counters
+// (i.e. how often Redis round-trips were avoided), not Redis's.
+func (n *nearCache) Stats() CacheStats {
+	return n.local.Stats()
+}
+
+// Describe and Collect implement prometheus.Collector by delegating to
+// the local layer, so NewRouter's collector registration works the same
+// whether the cache in use is a bare MemoryCache or a nearCache.
+func (n *nearCache) Describe(ch chan<- *prometheus.Desc) {
+	n.local.Describe(ch)
+}
+
+func (n *nearCache) Collect(ch chan<- prometheus.Metric) {
+	n.local.Collect(ch)
+}
+
+// Close stops the near-cache's local janitor goroutine.
+func (n *nearCache) Close() error {
+	return n.local.Close()
+}
+
 func MarshalCache(v any) ([]byte, error) {
 	return json.Marshal(v)
 }
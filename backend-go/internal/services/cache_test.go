@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"macroquant-intel/backend-go/internal/config"
+)
+
+func TestMemoryCacheEvictsLeastRecentlyUsedOnceFull(t *testing.T) {
+	m := NewMemoryCache(config.Config{MemoryCacheMaxEntries: 2, MemoryCacheSweepInterval: time.Minute})
+	defer m.Close()
+	ctx := context.Background()
+
+	_ = m.Set(ctx, "intel:a", []byte("a"), 0)
+	_ = m.Set(ctx, "intel:b", []byte("b"), 0)
+	m.Get(ctx, "intel:a") // touch a so b is now the least-recently-used
+	_ = m.Set(ctx, "intel:c", []byte("c"), 0)
+
+	if _, ok := m.Get(ctx, "intel:b"); ok {
+		t.Fatal("expected intel:b to have been evicted as LRU")
+	}
+	if _, ok := m.Get(ctx, "intel:a"); !ok {
+		t.Fatal("expected intel:a to still be cached")
+	}
+}
+
+func TestMemoryCacheGetExpiresEntriesPastTTL(t *testing.T) {
+	m := NewMemoryCache(config.Config{MemoryCacheMaxEntries: 10, MemoryCacheSweepInterval: time.Minute})
+	defer m.Close()
+	ctx := context.Background()
+
+	_ = m.Set(ctx, "leaders:x", []byte("x"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	if _, ok := m.Get(ctx, "leaders:x"); ok {
+		t.Fatal("expected expired entry to miss")
+	}
+	stats := m.Stats()
+	if stats.Misses == 0 {
+		t.Fatal("expected expiry to count as a miss")
+	}
+}
+
+func TestMemoryCacheStatsTracksPerPrefixCounters(t *testing.T) {
+	m := NewMemoryCache(config.Config{MemoryCacheMaxEntries: 10, MemoryCacheSweepInterval: time.Minute})
+	defer m.Close()
+	ctx := context.Background()
+
+	_ = m.Set(ctx, "derivatives:btc", []byte("v"), 0)
+	m.Get(ctx, "derivatives:btc")
+	m.Get(ctx, "derivatives:missing")
+
+	stats := m.Stats()
+	prefix, ok := stats.ByPrefix["derivatives"]
+	if !ok {
+		t.Fatal("expected derivatives prefix to be tracked")
+	}
+	if prefix.Hits != 1 || prefix.Misses != 1 {
+		t.Fatalf("expected 1 hit and 1 miss for derivatives prefix, got %+v", prefix)
+	}
+}
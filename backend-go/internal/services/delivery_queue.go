@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"macroquant-intel/backend-go/internal/reqlog"
+)
+
+// DeliveryMethod is the HTTP verb a queued DeliveryRequest dispatches
+// with. DeliveryQueue only durably retries mutating calls (POST/DELETE) -
+// GETs have nothing to retry-and-forget, since PythonClient's
+// FetchJSON* methods already serve a lastgood/cached fallback on failure.
+type DeliveryMethod string
+
+const (
+	DeliveryPost   DeliveryMethod = "POST"
+	DeliveryDelete DeliveryMethod = "DELETE"
+)
+
+// DeliveryRequest is one durable, at-least-once delivery: a mutating call
+// against the Python API that DeliveryQueue persists to disk before
+// Enqueue returns, so it survives a Go process restart (or a Python
+// outage longer than RunIntel's inline 3-attempt loop tolerates).
+// TargetID keys de-dup/cancel: enqueuing a second request for the same
+// TargetID (e.g. a re-submitted intel job) replaces the pending one
+// rather than piling up duplicate deliveries for the same entity.
+type DeliveryRequest struct {
+	ID        string          `json:"id"`
+	TargetID  string          `json:"target_id"`
+	Method    DeliveryMethod  `json:"method"`
+	Path      string          `json:"path"`
+	Body      json.RawMessage `json:"body,omitempty"`
+	Attempts  int             `json:"attempts"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// deliveryBackoffSteps is deliverLoop's retry cadence. Unlike
+// backoffSteps (derivatives_client.go), which gives up once exhausted,
+// a queued delivery's whole point is to outlast a handful of attempts -
+// so deliveryWait clamps to the last step and retries indefinitely
+// instead of failing the delivery.
+var deliveryBackoffSteps = []time.Duration{
+	1 * time.Second,
+	5 * time.Second,
+	15 * time.Second,
+	30 * time.Second,
+	1 * time.Minute,
+}
+
+// deliveryDispatchTimeout bounds a single delivery attempt against the
+// Python API, independent of the caller's original request context (which
+// is long gone by the time a queued delivery is dispatched).
+const deliveryDispatchTimeout = 30 * time.Second
+
+// DeliveryQueue is a durable request queue for PythonClient POST/DELETE
+// calls: Enqueue persists the request as a JSON segment file under dir and
+// returns immediately, and a bounded pool of sender goroutines dispatches
+// persisted requests against client with exponential backoff, independent
+// of the current inline retry loop in RunIntel. This is the same
+// per-target-queue-plus-bounded-worker-pool shape an ActivityPub delivery
+// worker uses for per-host queues, applied here to the one upstream (the
+// Python API) this backend has to keep in sync with across restarts.
+type DeliveryQueue struct {
+	dir    string
+	client *PythonClient
+
+	mu      sync.Mutex
+	pending map[string]*DeliveryRequest // keyed by TargetID
+
+	workCh chan *DeliveryRequest
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewDeliveryQueue builds a DeliveryQueue rooted at dir, replaying any
+// segment files already on disk (left behind by a process that didn't
+// finish draining) into pending before starting senders sender
+// goroutines. senders <= 0 falls back to 4.
+func NewDeliveryQueue(dir string, client *PythonClient, senders int) (*DeliveryQueue, error) {
+	if senders <= 0 {
+		senders = 4
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("delivery queue: %w", err)
+	}
+	q := &DeliveryQueue{
+		dir:     dir,
+		client:  client,
+		pending: make(map[string]*DeliveryRequest),
+		workCh:  make(chan *DeliveryRequest, 256),
+		stopCh:  make(chan struct{}),
+	}
+	if err := q.loadSegments(); err != nil {
+		return nil, err
+	}
+	for i := 0; i < senders; i++ {
+		q.wg.Add(1)
+		go q.sendLoop()
+	}
+	return q, nil
+}
+
+func (q *DeliveryQueue) segmentPath(id string) string {
+	return filepath.Join(q.dir, id+".json")
+}
+
+// loadSegments replays every segment file under dir into pending and
+// workCh, so a delivery queued just before a crash or deploy still gets
+// sent once the new process starts.
+func (q *DeliveryQueue) loadSegments() error {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return fmt.Errorf("delivery queue: reading segments: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(q.dir, entry.Name()))
+		if err != nil {
+			reqlog.Logger.Warn("delivery queue: skipping unreadable segment", "file", entry.Name(), "err", err.Error())
+			continue
+		}
+		var req DeliveryRequest
+		if err := json.Unmarshal(b, &req); err != nil {
+			reqlog.Logger.Warn("delivery queue: skipping corrupt segment", "file", entry.Name(), "err", err.Error())
+			continue
+		}
+		q.mu.Lock()
+		q.pending[req.TargetID] = &req
+		q.mu.Unlock()
+		q.workCh <- &req
+	}
+	return nil
+}
+
+// Enqueue persists req as a segment file and schedules it for dispatch,
+// replacing any pending delivery already queued for targetID. The
+// superseded request's in-flight attempt, if any, notices it's no longer
+// the pending entry for its target and abandons itself without retrying,
+// so callers only ever need to reason about "the latest call wins" per
+// target. The superseded request's own segment file is removed so a
+// restart's loadSegments can't resurrect it as pending again.
+func (q *DeliveryQueue) Enqueue(targetID string, method DeliveryMethod, path string, body []byte) (string, error) {
+	req := &DeliveryRequest{
+		ID:        newDeliveryID(),
+		TargetID:  targetID,
+		Method:    method,
+		Path:      path,
+		Body:      body,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := q.persist(req); err != nil {
+		return "", err
+	}
+	q.mu.Lock()
+	superseded, hadPending := q.pending[targetID]
+	q.pending[targetID] = req
+	q.mu.Unlock()
+	if hadPending {
+		_ = os.Remove(q.segmentPath(superseded.ID))
+	}
+	q.workCh <- req
+	return req.ID, nil
+}
+
+// Cancel drops targetID's pending delivery, if any, and deletes its
+// segment so it isn't replayed on the next restart. It reports whether a
+// pending delivery was found.
+func (q *DeliveryQueue) Cancel(targetID string) bool {
+	q.mu.Lock()
+	req, ok := q.pending[targetID]
+	if ok {
+		delete(q.pending, targetID)
+	}
+	q.mu.Unlock()
+	if !ok {
+		return false
+	}
+	_ = os.Remove(q.segmentPath(req.ID))
+	return true
+}
+
+func (q *DeliveryQueue) persist(req *DeliveryRequest) error {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(q.segmentPath(req.ID), b, 0o644)
+}
+
+func (q *DeliveryQueue) sendLoop() {
+	defer q.wg.Done()
+	for {
+		select {
+		case <-q.stopCh:
+			return
+		case req := <-q.workCh:
+			q.deliver(req)
+		}
+	}
+}
+
+// deliver retries req against q.client until it succeeds, is superseded
+// by a newer Enqueue for the same target, is canceled, or the queue is
+// closed.
+func (q *DeliveryQueue) deliver(req *DeliveryRequest) {
+	for {
+		if !q.stillPending(req) {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), deliveryDispatchTimeout)
+		var out any
+		var err error
+		switch req.Method {
+		case DeliveryDelete:
+			_, err = q.client.DeleteJSONWithStatus(ctx, req.Path, &out)
+		default:
+			err = q.client.PostJSON(ctx, req.Path, req.Body, &out)
+		}
+		cancel()
+		req.Attempts++
+
+		if err == nil {
+			q.complete(req)
+			return
+		}
+
+		reqlog.Logger.Warn("delivery queue: attempt failed",
+			"target_id", req.TargetID, "path", req.Path, "attempt", req.Attempts, "err", err.Error())
+
+		select {
+		case <-time.After(deliveryWait(req.Attempts)):
+		case <-q.stopCh:
+			return
+		}
+	}
+}
+
+// stillPending reports whether req is still the pending delivery for its
+// target - false once Cancel has dropped it or a newer Enqueue has
+// superseded it.
+func (q *DeliveryQueue) stillPending(req *DeliveryRequest) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	current, ok := q.pending[req.TargetID]
+	return ok && current.ID == req.ID
+}
+
+func (q *DeliveryQueue) complete(req *DeliveryRequest) {
+	q.mu.Lock()
+	if current, ok := q.pending[req.TargetID]; ok && current.ID == req.ID {
+		delete(q.pending, req.TargetID)
+	}
+	q.mu.Unlock()
+	_ = os.Remove(q.segmentPath(req.ID))
+}
+
+// deliveryWait returns deliveryBackoffSteps[attempt-1], clamped to the
+// last step once attempt exceeds its length, so retries settle into a
+// steady cadence instead of growing unbounded.
+func deliveryWait(attempt int) time.Duration {
+	if attempt <= 0 {
+		return deliveryBackoffSteps[0]
+	}
+	if attempt > len(deliveryBackoffSteps) {
+		return deliveryBackoffSteps[len(deliveryBackoffSteps)-1]
+	}
+	return deliveryBackoffSteps[attempt-1]
+}
+
+// Close stops every sender goroutine, abandoning any in-flight attempt's
+// retry wait. Pending segments remain on disk and replay on the next
+// NewDeliveryQueue.
+func (q *DeliveryQueue) Close() {
+	q.stopOnce()
+	q.wg.Wait()
+}
+
+func (q *DeliveryQueue) stopOnce() {
+	select {
+	case <-q.stopCh:
+	default:
+		close(q.stopCh)
+	}
+}
+
+// newDeliveryID mints a delivery ID using the same crypto/rand hex
+// convention as reqlog.NewRequestID, with a UnixNano fallback if
+// crypto/rand is unavailable.
+func newDeliveryID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("dlv-%d", time.Now().UnixNano())
+	}
+	return "dlv-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"macroquant-intel/backend-go/internal/config"
+)
+
+// countingHandler records every request it receives and fails the first
+// failUntil of them, so tests can assert DeliveryQueue retries past a
+// transient upstream failure before succeeding.
+type countingHandler struct {
+	mu        sync.Mutex
+	paths     []string
+	calls     int32
+	failUntil int32
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	h.paths = append(h.paths, r.URL.Path)
+	h.mu.Unlock()
+
+	n := atomic.AddInt32(&h.calls, 1)
+	if n <= h.failUntil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+}
+
+func (h *countingHandler) callCount() int {
+	return int(atomic.LoadInt32(&h.calls))
+}
+
+func newTestQueue(t *testing.T, h *countingHandler) *DeliveryQueue {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	py := NewPythonClient(config.Config{PyBaseURL: srv.URL, RequestTimeout: time.Second})
+	q, err := NewDeliveryQueue(t.TempDir(), py, 2)
+	if err != nil {
+		t.Fatalf("NewDeliveryQueue: %v", err)
+	}
+	t.Cleanup(q.Close)
+	return q
+}
+
+func TestDeliveryQueueDispatchesAndPersistsUntilSuccess(t *testing.T) {
+	h := &countingHandler{}
+	q := newTestQueue(t, h)
+
+	if _, err := q.Enqueue("job-1", DeliveryPost, "/intel/jobs/job-1", []byte(`{}`)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for h.callCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if h.callCount() == 0 {
+		t.Fatalf("expected the sender pool to dispatch the queued request")
+	}
+
+	for time.Now().Before(deadline) {
+		if entries, err := os.ReadDir(q.dir); err == nil && len(entries) == 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected the segment file to be removed once the delivery succeeded, got %d on disk", len(entries))
+	}
+}
+
+func TestDeliveryQueueRetriesOnFailureBeforeSucceeding(t *testing.T) {
+	h := &countingHandler{failUntil: 2}
+	deliveryBackoffSteps = []time.Duration{10 * time.Millisecond}
+	q := newTestQueue(t, h)
+
+	if _, err := q.Enqueue("job-2", DeliveryPost, "/intel/jobs/job-2", []byte(`{}`)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for h.callCount() < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := h.callCount(); got < 3 {
+		t.Fatalf("expected at least 3 attempts (2 failures + 1 success), got %d", got)
+	}
+}
+
+func TestDeliveryQueueEnqueueDedupsByTargetID(t *testing.T) {
+	h := &countingHandler{failUntil: 1000} // never succeeds, so both enqueues are still pending when we check
+	q := newTestQueue(t, h)
+
+	if _, err := q.Enqueue("job-3", DeliveryPost, "/intel/jobs/job-3", []byte(`{"v":1}`)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if _, err := q.Enqueue("job-3", DeliveryPost, "/intel/jobs/job-3", []byte(`{"v":2}`)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	q.mu.Lock()
+	n := len(q.pending)
+	q.mu.Unlock()
+	if n != 1 {
+		t.Fatalf("expected a second Enqueue for the same target to replace the first, got %d pending entries", n)
+	}
+}
+
+func TestDeliveryQueueEnqueueRemovesSupersededSegment(t *testing.T) {
+	h := &countingHandler{failUntil: 1000} // never succeeds, so the superseded segment would still be on disk if leaked
+	q := newTestQueue(t, h)
+
+	firstID, err := q.Enqueue("job-5", DeliveryPost, "/intel/jobs/job-5", []byte(`{"v":1}`))
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if _, err := os.Stat(q.segmentPath(firstID)); err != nil {
+		t.Fatalf("expected first segment to exist before it's superseded: %v", err)
+	}
+
+	if _, err := q.Enqueue("job-5", DeliveryPost, "/intel/jobs/job-5", []byte(`{"v":2}`)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	if _, err := os.Stat(q.segmentPath(firstID)); !os.IsNotExist(err) {
+		t.Fatalf("expected superseded segment file to be removed, stat err: %v", err)
+	}
+
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected exactly 1 segment file on disk after supersede, got %d", len(entries))
+	}
+}
+
+func TestDeliveryQueueCancelRemovesPending(t *testing.T) {
+	h := &countingHandler{failUntil: 1000} // never succeeds, so Cancel races a real retry loop
+	deliveryBackoffSteps = []time.Duration{50 * time.Millisecond}
+	q := newTestQueue(t, h)
+
+	if _, err := q.Enqueue("job-4", DeliveryPost, "/intel/jobs/job-4", []byte(`{}`)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if !q.Cancel("job-4") {
+		t.Fatalf("expected Cancel to find the just-enqueued delivery")
+	}
+	if q.Cancel("job-4") {
+		t.Fatalf("expected a second Cancel to report nothing pending")
+	}
+}
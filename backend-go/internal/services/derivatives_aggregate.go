@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"macroquant-intel/backend-go/internal/models"
+)
+
+// Weighting schemes GetAggregated accepts via the caller's query param.
+const (
+	WeightEqual  = "equal"
+	WeightOI     = "oi-weighted"
+	WeightVolume = "volume-weighted"
+)
+
+type venueFetch struct {
+	exchange string
+	resp     models.DerivativesResponse
+	warn     string
+	err      error
+	latency  time.Duration
+}
+
+// GetAggregated fans a single symbol out to every venue in
+// c.enabledVenues concurrently, then folds the results into one
+// DerivativesAggregated: per-venue series/health plus a composite view
+// combined per weighting. The aggregated result is cached as a whole,
+// separately from the single-venue Get cache, since it has its own shape
+// and its own venue fan-out cost to amortize.
+func (c *DerivativesClient) GetAggregated(ctx context.Context, symbol string, weighting string) (models.DerivativesAggregated, error) {
+	symbol = strings.ToUpper(symbol)
+	weighting = normalizeWeighting(weighting)
+	key := fmt.Sprintf("deriv:all:%s:%s", weighting, symbol)
+
+	if c.cache != nil {
+		if b, ok := c.cache.Get(ctx, key); ok {
+			var cached models.DerivativesAggregated
+			if err := UnmarshalCache(b, &cached); err == nil {
+				return cached, nil
+			}
+		}
+	}
+
+	results := make([]venueFetch, len(c.enabledVenues))
+	var wg sync.WaitGroup
+	for i, exchange := range c.enabledVenues {
+		wg.Add(1)
+		go func(i int, exchange string) {
+			defer wg.Done()
+			start := time.Now()
+			resp, warn, err := c.fetch(ctx, exchange, symbol)
+			results[i] = venueFetch{exchange: exchange, resp: resp, warn: warn, err: err, latency: time.Since(start)}
+		}(i, exchange)
+	}
+	wg.Wait()
+
+	agg := models.DerivativesAggregated{
+		Ts:     time.Now().UTC().Format(time.RFC3339),
+		Symbol: symbol,
+		Venues: make(map[string]models.DerivativesResponse, len(results)),
+		Health: make(map[string]models.DerivativesVenueHealth, len(results)),
+	}
+
+	var ok int
+	for _, r := range results {
+		health := models.DerivativesVenueHealth{
+			Exchange:    r.exchange,
+			LatencyMs:   int64(r.latency / time.Millisecond),
+			RateLimited: errors.Is(r.err, errRateLimited),
+		}
+		if dropped, until := c.failover.status(r.exchange); dropped {
+			health.Dropped = true
+			health.CoolOffUntil = until.UTC().Format(time.RFC3339)
+		}
+		if r.err != nil {
+			health.DegradedMode = true
+			health.Error = r.err.Error()
+			agg.Health[r.exchange] = health
+			continue
+		}
+		if r.warn != "" {
+			health.Error = r.warn
+		}
+		agg.Venues[r.exchange] = r.resp
+		agg.Health[r.exchange] = health
+		ok++
+	}
+
+	agg.Composite = composeDerivatives(agg.Venues, weighting)
+
+	if c.cache != nil && ok > 0 {
+		if b, err := MarshalCache(agg); err == nil {
+			_ = c.cache.Set(ctx, key, b, c.ttl)
+		}
+	}
+
+	if ok == 0 {
+		return agg, errRateLimited
+	}
+	return agg, nil
+}
+
+// composeDerivatives combines every reachable venue's latest funding rate,
+// funding z-score, and open interest into one DerivativesComposite.
+// "volume-weighted" degrades to oi-weighted, since none of the venue
+// clients fetch a volume series to weight by - the same "fail safe to a
+// simpler scheme" pattern NewEmbeddingReranker uses when embeddings
+// aren't configured.
+func composeDerivatives(venues map[string]models.DerivativesResponse, weighting string) models.DerivativesComposite {
+	composite := models.DerivativesComposite{Weighting: weighting}
+	if len(venues) == 0 {
+		return composite
+	}
+
+	weights := make(map[string]float64, len(venues))
+	var totalWeight float64
+	for exchange, v := range venues {
+		w := 1.0
+		if weighting == WeightOI || weighting == WeightVolume {
+			w = v.OI.Latest
+			if w <= 0 {
+				w = 0
+			}
+		}
+		weights[exchange] = w
+		totalWeight += w
+	}
+	if totalWeight <= 0 {
+		// No venue reported a usable OI weight; fall back to an equal
+		// split rather than dividing by zero.
+		for exchange := range weights {
+			weights[exchange] = 1
+		}
+		totalWeight = float64(len(weights))
+	}
+
+	var fundingRate, fundingZ, openInterest float64
+	for exchange, v := range venues {
+		share := weights[exchange] / totalWeight
+		fundingRate += share * v.Funding.Latest
+		fundingZ += share * v.Computed.FundingZ
+		openInterest += v.OI.Latest
+	}
+
+	composite.FundingRate = fundingRate
+	composite.FundingZ = fundingZ
+	composite.OpenInterest = openInterest
+	return composite
+}
+
+// normalizeWeighting maps an unrecognized or empty weighting param to the
+// ticket's headline scheme (oi-weighted), so exchange=all has a sane
+// default composite without the caller having to know the options.
+func normalizeWeighting(weighting string) string {
+	switch weighting {
+	case WeightEqual, WeightOI, WeightVolume:
+		return weighting
+	default:
+		return WeightOI
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+
+	"macroquant-intel/backend-go/internal/models"
+)
+
+func TestComposeDerivativesOIWeighted(t *testing.T) {
+	venues := map[string]models.DerivativesResponse{
+		"binance": {
+			Funding:  models.DerivativesSeries{Latest: 0.01},
+			OI:       models.DerivativesSeries{Latest: 900},
+			Computed: models.DerivativesComputed{FundingZ: 1},
+		},
+		"okx": {
+			Funding:  models.DerivativesSeries{Latest: 0.05},
+			OI:       models.DerivativesSeries{Latest: 100},
+			Computed: models.DerivativesComputed{FundingZ: -1},
+		},
+	}
+
+	composite := composeDerivatives(venues, WeightOI)
+	if composite.OpenInterest != 1000 {
+		t.Fatalf("expected total OI 1000, got %v", composite.OpenInterest)
+	}
+	// binance has 9x okx's OI, so the composite funding rate should sit
+	// much closer to binance's 0.01 than okx's 0.05.
+	if composite.FundingRate <= 0.01 || composite.FundingRate >= 0.02 {
+		t.Fatalf("expected oi-weighted funding rate dominated by binance, got %v", composite.FundingRate)
+	}
+}
+
+func TestComposeDerivativesEqualWeighted(t *testing.T) {
+	venues := map[string]models.DerivativesResponse{
+		"binance": {Funding: models.DerivativesSeries{Latest: 0.02}, OI: models.DerivativesSeries{Latest: 900}},
+		"okx":     {Funding: models.DerivativesSeries{Latest: 0.04}, OI: models.DerivativesSeries{Latest: 100}},
+	}
+
+	composite := composeDerivatives(venues, WeightEqual)
+	if composite.FundingRate != 0.03 {
+		t.Fatalf("expected an equal-weighted funding rate of 0.03, got %v", composite.FundingRate)
+	}
+}
+
+func TestComposeDerivativesNoVenuesReturnsZeroValue(t *testing.T) {
+	composite := composeDerivatives(map[string]models.DerivativesResponse{}, WeightOI)
+	if composite.FundingRate != 0 || composite.OpenInterest != 0 {
+		t.Fatalf("expected a zero-value composite for no venues, got %+v", composite)
+	}
+}
+
+func TestNormalizeWeightingDefaultsToOIWeighted(t *testing.T) {
+	if got := normalizeWeighting("bogus"); got != WeightOI {
+		t.Fatalf("expected unrecognized weighting to default to %s, got %s", WeightOI, got)
+	}
+	if got := normalizeWeighting(WeightEqual); got != WeightEqual {
+		t.Fatalf("expected %s to pass through unchanged, got %s", WeightEqual, got)
+	}
+}
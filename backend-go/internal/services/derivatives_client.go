@@ -8,16 +8,23 @@ import (
 	"math"
 	"net/http"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
+	"golang.org/x/sync/singleflight"
+
 	"macroquant-intel/backend-go/internal/config"
+	"macroquant-intel/backend-go/internal/metrics"
 	"macroquant-intel/backend-go/internal/models"
+	"macroquant-intel/backend-go/internal/reqlog"
 )
 
 var errRateLimited = errors.New("rate_limited")
 
+// errVenueCoolingOff is returned by fetch when a venue's automatic
+// failover has dropped it for repeated rate-limiting; see venueFailover.
+var errVenueCoolingOff = errors.New("venue_cooling_off")
+
 var backoffSteps = []time.Duration{
 	1 * time.Second,
 	2 * time.Second,
@@ -27,25 +34,50 @@ var backoffSteps = []time.Duration{
 	30 * time.Second,
 }
 
+// defaultVenues is used when cfg has no enabled-venue list configured.
+var defaultVenues = []string{"binance", "okx", "bybit"}
+
 type DerivativesClient struct {
 	hc    *http.Client
 	cache Cache
 	ttl   time.Duration
+
+	venues        map[string]DerivativesVenue
+	enabledVenues []string
+	failover      *venueFailover
+
+	sf           singleflight.Group
+	refreshAhead time.Duration
+	refreshSem   chan struct{}
 }
 
 func NewDerivativesClient(cfg config.Config, cache Cache) *DerivativesClient {
+	hc := &http.Client{
+		Timeout: cfg.RequestTimeout,
+	}
+	enabled := cfg.DerivativesEnabledVenues
+	if len(enabled) == 0 {
+		enabled = defaultVenues
+	}
+	refreshPoolSize := cfg.DerivativesRefreshPoolSize
+	if refreshPoolSize <= 0 {
+		refreshPoolSize = 4
+	}
 	return &DerivativesClient{
-		hc: &http.Client{
-			Timeout: cfg.RequestTimeout,
-		},
-		cache: cache,
-		ttl:   cfg.CacheTTLDerv,
+		hc:            hc,
+		cache:         cache,
+		ttl:           cfg.CacheTTLDerv,
+		venues:        newVenueRegistry(hc),
+		enabledVenues: enabled,
+		failover:      newVenueFailover(cfg.DerivativesRateLimitWindow, cfg.DerivativesRateLimitMax, cfg.DerivativesCoolOff),
+		refreshAhead:  cfg.DerivativesRefreshAhead,
+		refreshSem:    make(chan struct{}, refreshPoolSize),
 	}
 }
 
 func (c *DerivativesClient) Get(ctx context.Context, exchange string, symbol string) (models.DerivativesResponse, models.DerivativesHealth) {
 	start := time.Now()
-	exchange = normalizeExchange(exchange)
+	exchange = c.normalizeExchange(exchange)
 	symbol = strings.ToUpper(symbol)
 	key := fmt.Sprintf("deriv:%s:%s", exchange, symbol)
 	lastGoodKey := fmt.Sprintf("deriv:lastgood:%s:%s", exchange, symbol)
@@ -54,6 +86,10 @@ func (c *DerivativesClient) Get(ctx context.Context, exchange string, symbol str
 		if b, ok := c.cache.Get(ctx, key); ok {
 			var cached models.DerivativesResponse
 			if err := UnmarshalCache(b, &cached); err == nil {
+				if c.isStaleEnoughToRefresh(cached.Ts) {
+					metrics.CacheEvents.WithLabelValues("deriv", "stale").Inc()
+					c.scheduleRefresh(exchange, symbol, key, lastGoodKey)
+				}
 				return cached, models.DerivativesHealth{
 					LatencyMs: int64(time.Since(start) / time.Millisecond),
 					CacheHit:  true,
@@ -62,7 +98,7 @@ func (c *DerivativesClient) Get(ctx context.Context, exchange string, symbol str
 		}
 	}
 
-	resp, warn, err := c.fetch(ctx, exchange, symbol)
+	resp, warn, err := c.fetchCoalesced(ctx, key, exchange, symbol)
 	health := models.DerivativesHealth{
 		LatencyMs: int64(time.Since(start) / time.Millisecond),
 		CacheHit:  false,
@@ -101,21 +137,38 @@ func (c *DerivativesClient) Get(ctx context.Context, exchange string, symbol str
 	return resp, health
 }
 
-func normalizeExchange(exchange string) string {
+// normalizeExchange maps a caller-supplied exchange name to a registered
+// DerivativesVenue's key, defaulting to "binance" for anything unknown so
+// callers don't have to know the registered set up front.
+func (c *DerivativesClient) normalizeExchange(exchange string) string {
+	e := strings.ToLower(strings.TrimSpace(exchange))
+	if _, ok := c.venues[e]; ok {
+		return e
+	}
 	return "binance"
 }
 
+// fetch fans a symbol out to exchange's registered DerivativesVenue. It's
+// the only place that knows about venueFailover and the venue registry -
+// adding a new exchange means registering a DerivativesVenue in
+// newVenueRegistry, not touching this method.
 func (c *DerivativesClient) fetch(ctx context.Context, exchange string, symbol string) (models.DerivativesResponse, string, error) {
-	return c.fetchBinance(ctx, symbol)
-}
+	venue, ok := c.venues[exchange]
+	if !ok {
+		return models.DerivativesResponse{}, "", fmt.Errorf("unknown derivatives venue %q", exchange)
+	}
+	if !c.failover.allowed(exchange) {
+		return models.DerivativesResponse{}, "", errVenueCoolingOff
+	}
 
-func (c *DerivativesClient) fetchBinance(ctx context.Context, symbol string) (models.DerivativesResponse, string, error) {
-	fundingSeries, fundingLatest, fundErr := c.fetchBinanceFunding(ctx, symbol)
-	oiSeries, oiLatest, oiErr := c.fetchBinanceOI(ctx, symbol)
+	instSymbol := venue.NormalizeSymbol(symbol)
+	fundingSeries, fundingLatest, fundErr := venue.Funding(ctx, instSymbol)
+	oiSeries, oiLatest, oiErr := venue.OpenInterest(ctx, instSymbol)
 
 	warn := ""
 	if fundErr != nil || oiErr != nil {
 		if errors.Is(fundErr, errRateLimited) || errors.Is(oiErr, errRateLimited) {
+			c.failover.recordRateLimited(exchange)
 			return models.DerivativesResponse{}, "", errRateLimited
 		}
 		if fundErr != nil {
@@ -125,51 +178,86 @@ func (c *DerivativesClient) fetchBinance(ctx context.Context, symbol string) (mo
 		}
 	}
 
-	return buildDerivativesResponse("binance", symbol, fundingSeries, fundingLatest, oiSeries, oiLatest), warn, nil
+	return buildDerivativesResponse(exchange, symbol, fundingSeries, fundingLatest, oiSeries, oiLatest), warn, nil
 }
 
-func (c *DerivativesClient) fetchOKX(ctx context.Context, symbol string) (models.DerivativesResponse, string, error) {
-	instID := okxInstID(symbol)
-	fundingSeries, fundingLatest, fundErr := c.fetchOKXFunding(ctx, instID)
-	oiSeries, oiLatest, oiErr := c.fetchOKXOI(ctx, instID)
-
-	warn := ""
-	if fundErr != nil || oiErr != nil {
-		if errors.Is(fundErr, errRateLimited) || errors.Is(oiErr, errRateLimited) {
-			return models.DerivativesResponse{}, "", errRateLimited
-		}
-		if fundErr != nil {
-			warn = "data_missing_funding"
-		} else if oiErr != nil {
-			warn = "data_missing_oi"
-		}
-	}
+// derivativesFetchResult is the value type passed through c.sf, so a single
+// singleflight.Do call can carry fetch's three return values to every
+// caller it's shared with.
+type derivativesFetchResult struct {
+	resp models.DerivativesResponse
+	warn string
+}
 
-	return buildDerivativesResponse("okx", symbol, fundingSeries, fundingLatest, oiSeries, oiLatest), warn, nil
+// fetchCoalesced wraps fetch in a singleflight.Group keyed by the cache
+// key, so N concurrent misses (or SWR refreshes) for the same
+// exchange/symbol trigger exactly one upstream fetch instead of each
+// amplifying load on the venue independently.
+func (c *DerivativesClient) fetchCoalesced(ctx context.Context, key, exchange, symbol string) (models.DerivativesResponse, string, error) {
+	v, err, shared := c.sf.Do(key, func() (any, error) {
+		resp, warn, ferr := c.fetch(ctx, exchange, symbol)
+		return derivativesFetchResult{resp: resp, warn: warn}, ferr
+	})
+	if shared {
+		metrics.CacheCoalesced.WithLabelValues(keyPrefix(key)).Inc()
+	}
+	r := v.(derivativesFetchResult)
+	return r.resp, r.warn, err
 }
 
-func (c *DerivativesClient) fetchBybit(ctx context.Context, symbol string) (models.DerivativesResponse, string, error) {
-	fundingSeries, fundingLatest, fundErr := c.fetchBybitFunding(ctx, symbol)
-	oiSeries, oiLatest, oiErr := c.fetchBybitOI(ctx, symbol)
+// isStaleEnoughToRefresh reports whether a cache entry stamped at ts has
+// aged past c.ttl-c.refreshAhead, so Get can serve it immediately while
+// kicking off an async refresh (stale-while-revalidate) rather than
+// blocking the caller on one. A zero or unconfigured refreshAhead disables
+// SWR entirely, since there'd be nothing short of the TTL to refresh ahead
+// of.
+func (c *DerivativesClient) isStaleEnoughToRefresh(ts string) bool {
+	if c.refreshAhead <= 0 || c.ttl <= 0 || c.refreshAhead >= c.ttl {
+		return false
+	}
+	stamped, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return false
+	}
+	return time.Since(stamped) >= c.ttl-c.refreshAhead
+}
 
-	warn := ""
-	if fundErr != nil || oiErr != nil {
-		if errors.Is(fundErr, errRateLimited) || errors.Is(oiErr, errRateLimited) {
-			return models.DerivativesResponse{}, "", errRateLimited
+// scheduleRefresh kicks off an async SWR refresh for key if the bounded
+// refresh pool (sized by config.DerivativesRefreshPoolSize) has a free
+// slot; it's a best-effort nudge, not a guarantee, so a burst of stale
+// reads just falls back to serving stale again until a slot frees up.
+// It shares fetchCoalesced with the ordinary fetch path, so a refresh
+// racing a concurrent cache-miss for the same key still only costs one
+// upstream call.
+func (c *DerivativesClient) scheduleRefresh(exchange, symbol, key, lastGoodKey string) {
+	select {
+	case c.refreshSem <- struct{}{}:
+	default:
+		return
+	}
+	go func() {
+		defer func() { <-c.refreshSem }()
+		ctx, cancel := context.WithTimeout(context.Background(), c.hc.Timeout)
+		defer cancel()
+		resp, _, err := c.fetchCoalesced(ctx, key, exchange, symbol)
+		if err != nil {
+			metrics.BackgroundRefreshFailures.WithLabelValues(keyPrefix(key)).Inc()
+			return
 		}
-		if fundErr != nil {
-			warn = "data_missing_funding"
-		} else if oiErr != nil {
-			warn = "data_missing_oi"
+		if c.cache == nil || resp.Ts == "" {
+			return
 		}
-	}
-
-	return buildDerivativesResponse("bybit", symbol, fundingSeries, fundingLatest, oiSeries, oiLatest), warn, nil
+		if b, merr := MarshalCache(resp); merr == nil {
+			_ = c.cache.Set(context.Background(), key, b, c.ttl)
+			_ = c.cache.Set(context.Background(), lastGoodKey, b, 1*time.Hour)
+		}
+	}()
 }
 
 func buildDerivativesResponse(exchange string, symbol string, fundingSeries []models.DerivativesPoint, fundingLatest float64, oiSeries []models.DerivativesPoint, oiLatest float64) models.DerivativesResponse {
 	now := time.Now().UTC().Format(time.RFC3339)
-	fundingZ := computeFundingZ(fundingSeries, fundingLatest)
+	classicalZ := computeFundingZClassical(fundingSeries, fundingLatest)
+	robustZ := computeFundingZRobust(fundingSeries, fundingLatest)
 	oiDelta := computeOIDelta(oiSeries, oiLatest)
 
 	return models.DerivativesResponse{
@@ -185,13 +273,24 @@ func buildDerivativesResponse(exchange string, symbol string, fundingSeries []mo
 			Series: oiSeries,
 		},
 		Computed: models.DerivativesComputed{
-			FundingZ:   fundingZ,
-			OIDeltaPct: oiDelta,
+			FundingZ:          robustZ,
+			FundingZClassical: classicalZ,
+			OIDeltaPct:        oiDelta,
 		},
 	}
 }
 
-func computeFundingZ(series []models.DerivativesPoint, latest float64) float64 {
+// fundingZMinSeries is the minimum series length computeFundingZRobust needs
+// before its median/MAD estimate is trusted; shorter windows fall back to
+// computeFundingZClassical since MAD is unstable on a handful of points.
+const fundingZMinSeries = 8
+
+// madToSigma rescales a MAD-based deviation to be comparable to a Gaussian
+// standard deviation (MAD * 1.4826 ≈ σ under normality, so dividing by it
+// is equivalent to multiplying by its inverse, 0.6745).
+const madToSigma = 0.6745
+
+func computeFundingZClassical(series []models.DerivativesPoint, latest float64) float64 {
 	if len(series) < 2 {
 		return 0
 	}
@@ -202,36 +301,92 @@ func computeFundingZ(series []models.DerivativesPoint, latest float64) float64 {
 	mean := mean(values)
 	std := stddev(values, mean)
 	eps := 1e-9
-	z := (latest - mean) / math.Max(std, eps)
+	return clampZ((latest - mean) / math.Max(std, eps))
+}
+
+// computeFundingZRobust computes a median/MAD-based z-score, which (unlike
+// computeFundingZClassical's mean/stddev) isn't wrecked by a single outlier
+// funding print. It falls back to the classical estimator when the series
+// is too short for MAD to be stable.
+func computeFundingZRobust(series []models.DerivativesPoint, latest float64) float64 {
+	if len(series) < fundingZMinSeries {
+		return computeFundingZClassical(series, latest)
+	}
+	values := make([]float64, 0, len(series))
+	for _, p := range series {
+		values = append(values, p.V)
+	}
+	m := median(values)
+	devs := make([]float64, 0, len(values))
+	for _, v := range values {
+		devs = append(devs, math.Abs(v-m))
+	}
+	mad := median(devs)
+	eps := 1e-9
+	return clampZ(madToSigma * (latest - m) / math.Max(mad, eps))
+}
+
+func clampZ(z float64) float64 {
 	if z > 5 {
-		z = 5
+		return 5
 	}
 	if z < -5 {
-		z = -5
+		return -5
 	}
 	return z
 }
 
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
 func computeOIDelta(series []models.DerivativesPoint, latest float64) float64 {
 	if len(series) < 2 {
 		return 0
 	}
 	sort.Slice(series, func(i, j int) bool { return series[i].T < series[j].T })
 	target := time.Now().Add(-24 * time.Hour).UnixMilli()
-	closest := series[0]
-	closestDiff := int64(math.Abs(float64(series[0].T - target)))
-	for _, p := range series[1:] {
-		diff := int64(math.Abs(float64(p.T - target)))
-		if diff < closestDiff {
-			closest = p
-			closestDiff = diff
-		}
-	}
-	base := closest.V
+	base := interpolateAt(series, target)
 	eps := 1e-9
 	return (latest - base) / math.Max(base, eps) * 100
 }
 
+// interpolateAt linearly interpolates series' value at time t, assuming
+// series is sorted ascending by T, so a 5-minute gap around the 24h-ago
+// mark doesn't jitter the OI delta the way snapping to the single closest
+// point did. t outside the series' range clamps to the nearest endpoint
+// rather than extrapolating.
+func interpolateAt(series []models.DerivativesPoint, t int64) float64 {
+	if t <= series[0].T {
+		return series[0].V
+	}
+	last := series[len(series)-1]
+	if t >= last.T {
+		return last.V
+	}
+	for i := 1; i < len(series); i++ {
+		if series[i].T >= t {
+			prev := series[i-1]
+			next := series[i]
+			if next.T == prev.T {
+				return prev.V
+			}
+			frac := float64(t-prev.T) / float64(next.T-prev.T)
+			return prev.V + frac*(next.V-prev.V)
+		}
+	}
+	return last.V
+}
+
 func mean(values []float64) float64 {
 	if len(values) == 0 {
 		return 0
@@ -255,19 +410,32 @@ func stddev(values []float64, mean float64) float64 {
 	return math.Sqrt(sum / float64(len(values)))
 }
 
-func (c *DerivativesClient) fetchWithBackoff(ctx context.Context, url string, out any) error {
+// fetchWithBackoff retries a GET against url using backoffSteps, recording
+// every attempt against ctx's reqlog.Entry (a no-op if the context carries
+// none). It's a free function rather than a DerivativesClient method so
+// DerivativesVenue implementations, which only hold an *http.Client, can
+// call it directly.
+func fetchWithBackoff(ctx context.Context, hc *http.Client, url string, out any) error {
+	entry := reqlog.FromContext(ctx)
 	var lastErr error
 	for i, wait := range backoffSteps {
-		status, err := c.doJSON(ctx, url, out)
+		status, err := doJSON(ctx, hc, url, out)
+		attempt := reqlog.UpstreamAttempt{URL: url, Attempt: i + 1, Status: status}
 		if err == nil {
+			entry.RecordUpstream(attempt)
 			return nil
 		}
 		lastErr = err
+		attempt.Err = err.Error()
 		if status == http.StatusTooManyRequests {
+			attempt.RateLimited = true
 			if i == len(backoffSteps)-1 {
+				entry.RecordUpstream(attempt)
 				return errRateLimited
 			}
 		}
+		attempt.WaitMs = wait.Milliseconds()
+		entry.RecordUpstream(attempt)
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
@@ -280,12 +448,12 @@ func (c *DerivativesClient) fetchWithBackoff(ctx context.Context, url string, ou
 	return errors.New("request_failed")
 }
 
-func (c *DerivativesClient) doJSON(ctx context.Context, url string, out any) (int, error) {
+func doJSON(ctx context.Context, hc *http.Client, url string, out any) (int, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return 0, err
 	}
-	res, err := c.hc.Do(req)
+	res, err := hc.Do(req)
 	if err != nil {
 		return 0, err
 	}
@@ -302,170 +470,6 @@ func (c *DerivativesClient) doJSON(ctx context.Context, url string, out any) (in
 	return res.StatusCode, nil
 }
 
-func (c *DerivativesClient) fetchBinanceFunding(ctx context.Context, symbol string) ([]models.DerivativesPoint, float64, error) {
-	url := fmt.Sprintf("https://fapi.binance.com/fapi/v1/fundingRate?symbol=%s&limit=1000", symbol)
-	var raw []struct {
-		FundingRate string `json:"fundingRate"`
-		FundingTime int64  `json:"fundingTime"`
-	}
-	if err := c.fetchWithBackoff(ctx, url, &raw); err != nil {
-		return nil, 0, err
-	}
-	points := make([]models.DerivativesPoint, 0, len(raw))
-	for _, r := range raw {
-		v, err := strconv.ParseFloat(r.FundingRate, 64)
-		if err != nil {
-			continue
-		}
-		points = append(points, models.DerivativesPoint{T: r.FundingTime, V: v})
-	}
-	points = filterByDays(points, 7)
-	latest := 0.0
-	if len(points) > 0 {
-		latest = points[len(points)-1].V
-	}
-	return points, latest, nil
-}
-
-func (c *DerivativesClient) fetchBinanceOI(ctx context.Context, symbol string) ([]models.DerivativesPoint, float64, error) {
-	histURL := fmt.Sprintf("https://fapi.binance.com/futures/data/openInterestHist?symbol=%s&period=5m&limit=500", symbol)
-	var raw []struct {
-		SumOpenInterest string `json:"sumOpenInterest"`
-		Timestamp       int64  `json:"timestamp"`
-	}
-	if err := c.fetchWithBackoff(ctx, histURL, &raw); err != nil {
-		return nil, 0, err
-	}
-	points := make([]models.DerivativesPoint, 0, len(raw))
-	for _, r := range raw {
-		v, err := strconv.ParseFloat(r.SumOpenInterest, 64)
-		if err != nil {
-			continue
-		}
-		points = append(points, models.DerivativesPoint{T: r.Timestamp, V: v})
-	}
-	latest := 0.0
-	if len(points) > 0 {
-		latest = points[len(points)-1].V
-	}
-	return points, latest, nil
-}
-
-func (c *DerivativesClient) fetchBybitFunding(ctx context.Context, symbol string) ([]models.DerivativesPoint, float64, error) {
-	url := fmt.Sprintf("https://api.bybit.com/v5/market/history-fund-rate?category=linear&symbol=%s&limit=200", symbol)
-	var raw struct {
-		Result struct {
-			List []struct {
-				FundingRate          string `json:"fundingRate"`
-				FundingRateTimestamp string `json:"fundingRateTimestamp"`
-			} `json:"list"`
-		} `json:"result"`
-	}
-	if err := c.fetchWithBackoff(ctx, url, &raw); err != nil {
-		return nil, 0, err
-	}
-	points := make([]models.DerivativesPoint, 0, len(raw.Result.List))
-	for _, r := range raw.Result.List {
-		v, err := strconv.ParseFloat(r.FundingRate, 64)
-		if err != nil {
-			continue
-		}
-		ts, _ := strconv.ParseInt(r.FundingRateTimestamp, 10, 64)
-		points = append(points, models.DerivativesPoint{T: ts, V: v})
-	}
-	points = filterByDays(points, 7)
-	latest := 0.0
-	if len(points) > 0 {
-		latest = points[0].V
-	}
-	return points, latest, nil
-}
-
-func (c *DerivativesClient) fetchBybitOI(ctx context.Context, symbol string) ([]models.DerivativesPoint, float64, error) {
-	url := fmt.Sprintf("https://api.bybit.com/v5/market/open-interest?category=linear&symbol=%s&intervalTime=5min&limit=50", symbol)
-	var raw struct {
-		Result struct {
-			List []struct {
-				OpenInterest string `json:"openInterest"`
-				Timestamp    string `json:"timestamp"`
-			} `json:"list"`
-		} `json:"result"`
-	}
-	if err := c.fetchWithBackoff(ctx, url, &raw); err != nil {
-		return nil, 0, err
-	}
-	points := make([]models.DerivativesPoint, 0, len(raw.Result.List))
-	for _, r := range raw.Result.List {
-		v, err := strconv.ParseFloat(r.OpenInterest, 64)
-		if err != nil {
-			continue
-		}
-		ts, _ := strconv.ParseInt(r.Timestamp, 10, 64)
-		points = append(points, models.DerivativesPoint{T: ts, V: v})
-	}
-	sort.Slice(points, func(i, j int) bool { return points[i].T < points[j].T })
-	latest := 0.0
-	if len(points) > 0 {
-		latest = points[len(points)-1].V
-	}
-	return points, latest, nil
-}
-
-func (c *DerivativesClient) fetchOKXFunding(ctx context.Context, instID string) ([]models.DerivativesPoint, float64, error) {
-	url := fmt.Sprintf("https://www.okx.com/api/v5/public/funding-rate-history?instId=%s", instID)
-	var raw struct {
-		Data []struct {
-			FundingRate string `json:"fundingRate"`
-			Ts          string `json:"ts"`
-		} `json:"data"`
-	}
-	if err := c.fetchWithBackoff(ctx, url, &raw); err != nil {
-		return nil, 0, err
-	}
-	points := make([]models.DerivativesPoint, 0, len(raw.Data))
-	for _, r := range raw.Data {
-		v, err := strconv.ParseFloat(r.FundingRate, 64)
-		if err != nil {
-			continue
-		}
-		ts, _ := strconv.ParseInt(r.Ts, 10, 64)
-		points = append(points, models.DerivativesPoint{T: ts, V: v})
-	}
-	points = filterByDays(points, 7)
-	latest := 0.0
-	if len(points) > 0 {
-		latest = points[0].V
-	}
-	return points, latest, nil
-}
-
-func (c *DerivativesClient) fetchOKXOI(ctx context.Context, instID string) ([]models.DerivativesPoint, float64, error) {
-	url := fmt.Sprintf("https://www.okx.com/api/v5/public/open-interest?instType=SWAP&instId=%s", instID)
-	var raw struct {
-		Data []struct {
-			OI string `json:"oi"`
-			Ts string `json:"ts"`
-		} `json:"data"`
-	}
-	if err := c.fetchWithBackoff(ctx, url, &raw); err != nil {
-		return nil, 0, err
-	}
-	points := make([]models.DerivativesPoint, 0, len(raw.Data))
-	for _, r := range raw.Data {
-		v, err := strconv.ParseFloat(r.OI, 64)
-		if err != nil {
-			continue
-		}
-		ts, _ := strconv.ParseInt(r.Ts, 10, 64)
-		points = append(points, models.DerivativesPoint{T: ts, V: v})
-	}
-	latest := 0.0
-	if len(points) > 0 {
-		latest = points[len(points)-1].V
-	}
-	return points, latest, nil
-}
-
 func filterByDays(points []models.DerivativesPoint, days int) []models.DerivativesPoint {
 	if len(points) == 0 {
 		return points
@@ -480,17 +484,3 @@ func filterByDays(points []models.DerivativesPoint, days int) []models.Derivativ
 	sort.Slice(out, func(i, j int) bool { return out[i].T < out[j].T })
 	return out
 }
-
-func okxInstID(symbol string) string {
-	if strings.Contains(symbol, "-") {
-		if strings.HasSuffix(symbol, "-SWAP") {
-			return symbol
-		}
-		return symbol + "-SWAP"
-	}
-	if strings.HasSuffix(symbol, "USDT") {
-		base := strings.TrimSuffix(symbol, "USDT")
-		return fmt.Sprintf("%s-USDT-SWAP", base)
-	}
-	return symbol + "-USDT-SWAP"
-}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+
+	"macroquant-intel/backend-go/internal/models"
+)
+
+func pointsFrom(values []float64) []models.DerivativesPoint {
+	points := make([]models.DerivativesPoint, len(values))
+	for i, v := range values {
+		points[i] = models.DerivativesPoint{T: int64(i) * 300_000, V: v}
+	}
+	return points
+}
+
+func TestComputeFundingZRobustResistsAnOutlier(t *testing.T) {
+	values := []float64{0.01, 0.011, 0.009, 0.0105, 0.0095, 0.01, 0.0102, 0.0098, 5.0}
+	series := pointsFrom(values)
+
+	robust := computeFundingZRobust(series, 0.0103)
+	classical := computeFundingZClassical(series, 0.0103)
+
+	if robust < -1 || robust > 1 {
+		t.Fatalf("expected the robust z-score for a near-median value to stay small, got %v", robust)
+	}
+	if classical >= robust {
+		t.Fatalf("expected the single 5.0 outlier to drag the classical estimator below the robust one; classical=%v robust=%v", classical, robust)
+	}
+}
+
+func TestComputeFundingZRobustFallsBackToClassicalOnShortSeries(t *testing.T) {
+	series := pointsFrom([]float64{0.01, 0.02, 0.03})
+	if got, want := computeFundingZRobust(series, 0.05), computeFundingZClassical(series, 0.05); got != want {
+		t.Fatalf("expected short series to fall back to the classical estimator, got %v want %v", got, want)
+	}
+}
+
+func TestMedianEvenAndOddLengths(t *testing.T) {
+	if got := median([]float64{1, 2, 3}); got != 2 {
+		t.Fatalf("expected median of odd-length slice to be 2, got %v", got)
+	}
+	if got := median([]float64{1, 2, 3, 4}); got != 2.5 {
+		t.Fatalf("expected median of even-length slice to be 2.5, got %v", got)
+	}
+}
+
+func TestInterpolateAtBracketsBetweenPoints(t *testing.T) {
+	series := []models.DerivativesPoint{{T: 0, V: 100}, {T: 1000, V: 200}}
+	if got := interpolateAt(series, 500); got != 150 {
+		t.Fatalf("expected the midpoint interpolation to be 150, got %v", got)
+	}
+}
+
+func TestInterpolateAtClampsOutsideRange(t *testing.T) {
+	series := []models.DerivativesPoint{{T: 0, V: 100}, {T: 1000, V: 200}}
+	if got := interpolateAt(series, -500); got != 100 {
+		t.Fatalf("expected a target before the series to clamp to the first point, got %v", got)
+	}
+	if got := interpolateAt(series, 5000); got != 200 {
+		t.Fatalf("expected a target after the series to clamp to the last point, got %v", got)
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// venueFailover tracks how often each derivatives venue has been
+// rate-limited in a rolling window and drops it from the aggregate for a
+// cool-off period once that count exceeds limit, so one venue throttling
+// requests doesn't keep eating retries (and the window's worth of backoff)
+// on every call until it recovers on its own.
+type venueFailover struct {
+	window  time.Duration
+	limit   int
+	coolOff time.Duration
+
+	mu        sync.Mutex
+	hits      map[string][]time.Time
+	coolUntil map[string]time.Time
+}
+
+// newVenueFailover builds a venueFailover from cfg's derivatives failover
+// settings, falling back to sane defaults for any zero value so a caller
+// with an unconfigured config.Config still gets working failover.
+func newVenueFailover(window time.Duration, limit int, coolOff time.Duration) *venueFailover {
+	if window <= 0 {
+		window = 5 * time.Minute
+	}
+	if limit <= 0 {
+		limit = 3
+	}
+	if coolOff <= 0 {
+		coolOff = 10 * time.Minute
+	}
+	return &venueFailover{
+		window:    window,
+		limit:     limit,
+		coolOff:   coolOff,
+		hits:      make(map[string][]time.Time),
+		coolUntil: make(map[string]time.Time),
+	}
+}
+
+// recordRateLimited notes that venue was just rate-limited, and drops it
+// for coolOff once more than limit such hits have landed within window.
+func (f *venueFailover) recordRateLimited(venue string) {
+	now := time.Now()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	cutoff := now.Add(-f.window)
+	hits := append(f.hits[venue], now)
+	kept := hits[:0]
+	for _, h := range hits {
+		if h.After(cutoff) {
+			kept = append(kept, h)
+		}
+	}
+	f.hits[venue] = kept
+
+	if len(kept) > f.limit {
+		f.coolUntil[venue] = now.Add(f.coolOff)
+	}
+}
+
+// allowed reports whether venue may be called right now: true unless it's
+// currently serving out a cool-off period from recordRateLimited.
+func (f *venueFailover) allowed(venue string) bool {
+	dropped, _ := f.status(venue)
+	return !dropped
+}
+
+// status reports whether venue is currently dropped for cool-off, and
+// until when, for surfacing in DerivativesVenueHealth. A cool-off that has
+// elapsed is cleared so the venue gets a clean rolling window on retry.
+func (f *venueFailover) status(venue string) (dropped bool, until time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	until, ok := f.coolUntil[venue]
+	if !ok {
+		return false, time.Time{}
+	}
+	if time.Now().After(until) {
+		delete(f.coolUntil, venue)
+		delete(f.hits, venue)
+		return false, time.Time{}
+	}
+	return true, until
+}
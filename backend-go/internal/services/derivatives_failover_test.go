@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVenueFailoverDropsVenueAfterRepeatedRateLimiting(t *testing.T) {
+	f := newVenueFailover(time.Minute, 2, 5*time.Minute)
+
+	if !f.allowed("binance") {
+		t.Fatalf("expected a fresh venue to be allowed")
+	}
+
+	f.recordRateLimited("binance")
+	f.recordRateLimited("binance")
+	if !f.allowed("binance") {
+		t.Fatalf("expected the venue to still be allowed at exactly the limit")
+	}
+
+	f.recordRateLimited("binance")
+	if f.allowed("binance") {
+		t.Fatalf("expected the venue to be dropped after exceeding the limit")
+	}
+
+	dropped, until := f.status("binance")
+	if !dropped || !until.After(time.Now()) {
+		t.Fatalf("expected status to report dropped with a future cool-off, got dropped=%v until=%v", dropped, until)
+	}
+}
+
+func TestVenueFailoverTracksVenuesIndependently(t *testing.T) {
+	f := newVenueFailover(time.Minute, 1, 5*time.Minute)
+
+	f.recordRateLimited("binance")
+	f.recordRateLimited("binance")
+	if f.allowed("binance") {
+		t.Fatalf("expected binance to be dropped")
+	}
+	if !f.allowed("okx") {
+		t.Fatalf("expected okx to be unaffected by binance's drop")
+	}
+}
+
+func TestVenueFailoverClearsAfterCoolOffElapses(t *testing.T) {
+	f := newVenueFailover(time.Minute, 1, 1*time.Millisecond)
+
+	f.recordRateLimited("binance")
+	f.recordRateLimited("binance")
+	if f.allowed("binance") {
+		t.Fatalf("expected binance to be dropped immediately after exceeding the limit")
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if !f.allowed("binance") {
+		t.Fatalf("expected binance to be allowed again once its cool-off elapsed")
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"macroquant-intel/backend-go/internal/models"
+)
+
+// countingVenue is a DerivativesVenue stub that counts how many times
+// Funding was actually called (once per upstream fetch; OpenInterest is
+// not counted), so tests can assert on upstream call counts rather than
+// wall-clock timing.
+type countingVenue struct {
+	calls int32
+}
+
+func (v *countingVenue) Name() string                         { return "binance" }
+func (v *countingVenue) NormalizeSymbol(symbol string) string { return symbol }
+
+func (v *countingVenue) Funding(ctx context.Context, symbol string) ([]models.DerivativesPoint, float64, error) {
+	atomic.AddInt32(&v.calls, 1)
+	time.Sleep(10 * time.Millisecond)
+	return pointsFrom([]float64{0.01, 0.011, 0.009}), 0.01, nil
+}
+
+func (v *countingVenue) OpenInterest(ctx context.Context, symbol string) ([]models.DerivativesPoint, float64, error) {
+	return pointsFrom([]float64{100, 101, 102}), 103, nil
+}
+
+func newTestDerivativesClient(venue *countingVenue) *DerivativesClient {
+	return &DerivativesClient{
+		venues:        map[string]DerivativesVenue{"binance": venue},
+		enabledVenues: []string{"binance"},
+		failover:      newVenueFailover(time.Minute, 3, time.Minute),
+		ttl:           time.Minute,
+		refreshAhead:  10 * time.Second,
+		refreshSem:    make(chan struct{}, 4),
+		hc:            &http.Client{Timeout: time.Second},
+	}
+}
+
+func TestFetchCoalescedDedupsConcurrentCalls(t *testing.T) {
+	venue := &countingVenue{}
+	c := newTestDerivativesClient(venue)
+
+	done := make(chan struct{})
+	for i := 0; i < 5; i++ {
+		go func() {
+			_, _, _ = c.fetchCoalesced(context.Background(), "deriv:binance:BTCUSDT", "binance", "BTCUSDT")
+			done <- struct{}{}
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		<-done
+	}
+
+	if got := atomic.LoadInt32(&venue.calls); got != 1 {
+		t.Fatalf("expected 5 concurrent callers to coalesce into 1 upstream fetch, got %d", got)
+	}
+}
+
+func TestIsStaleEnoughToRefresh(t *testing.T) {
+	c := newTestDerivativesClient(&countingVenue{})
+
+	fresh := time.Now().UTC().Format(time.RFC3339)
+	if c.isStaleEnoughToRefresh(fresh) {
+		t.Fatalf("expected a just-written entry to not need a refresh yet")
+	}
+
+	aged := time.Now().Add(-55 * time.Second).UTC().Format(time.RFC3339)
+	if !c.isStaleEnoughToRefresh(aged) {
+		t.Fatalf("expected an entry within refreshAhead of ttl's expiry to need a refresh")
+	}
+
+	if c.isStaleEnoughToRefresh("not-a-timestamp") {
+		t.Fatalf("expected an unparseable timestamp to not trigger a refresh")
+	}
+}
+
+func TestIsStaleEnoughToRefreshDisabledWhenRefreshAheadUnset(t *testing.T) {
+	c := newTestDerivativesClient(&countingVenue{})
+	c.refreshAhead = 0
+
+	aged := time.Now().Add(-55 * time.Second).UTC().Format(time.RFC3339)
+	if c.isStaleEnoughToRefresh(aged) {
+		t.Fatalf("expected SWR to be disabled when refreshAhead is unset")
+	}
+}
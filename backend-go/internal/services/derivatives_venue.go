@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"macroquant-intel/backend-go/internal/models"
+)
+
+// DerivativesVenue is one exchange's funding-rate and open-interest data
+// source. Each venue owns its own symbol formatting and endpoint URLs, so
+// adding a new exchange (Deribit, Kraken, BitMEX, ...) means writing a new
+// DerivativesVenue and registering it in newVenueRegistry - nothing in
+// DerivativesClient's fan-out or aggregation logic has to change.
+type DerivativesVenue interface {
+	// Name is the venue's registry key and the "exchange" value callers
+	// pass to DerivativesClient.Get/GetAggregated.
+	Name() string
+	// NormalizeSymbol maps a caller-facing symbol (already upper-cased,
+	// e.g. "BTCUSDT") to this venue's own instrument ID format.
+	NormalizeSymbol(symbol string) string
+	Funding(ctx context.Context, symbol string) ([]models.DerivativesPoint, float64, error)
+	OpenInterest(ctx context.Context, symbol string) ([]models.DerivativesPoint, float64, error)
+}
+
+// newVenueRegistry builds the fixed set of venues this build knows how to
+// talk to, all sharing hc the way DerivativesClient itself used to.
+func newVenueRegistry(hc *http.Client) map[string]DerivativesVenue {
+	venues := []DerivativesVenue{
+		newBinanceVenue(hc),
+		newOKXVenue(hc),
+		newBybitVenue(hc),
+	}
+	out := make(map[string]DerivativesVenue, len(venues))
+	for _, v := range venues {
+		out[v.Name()] = v
+	}
+	return out
+}
+
+// --- Binance ---
+
+type binanceVenue struct{ hc *http.Client }
+
+func newBinanceVenue(hc *http.Client) *binanceVenue { return &binanceVenue{hc: hc} }
+
+func (v *binanceVenue) Name() string { return "binance" }
+
+func (v *binanceVenue) NormalizeSymbol(symbol string) string { return strings.ToUpper(symbol) }
+
+func (v *binanceVenue) Funding(ctx context.Context, symbol string) ([]models.DerivativesPoint, float64, error) {
+	url := fmt.Sprintf("https://fapi.binance.com/fapi/v1/fundingRate?symbol=%s&limit=1000", symbol)
+	var raw []struct {
+		FundingRate string `json:"fundingRate"`
+		FundingTime int64  `json:"fundingTime"`
+	}
+	if err := fetchWithBackoff(ctx, v.hc, url, &raw); err != nil {
+		return nil, 0, err
+	}
+	points := make([]models.DerivativesPoint, 0, len(raw))
+	for _, r := range raw {
+		val, err := strconv.ParseFloat(r.FundingRate, 64)
+		if err != nil {
+			continue
+		}
+		points = append(points, models.DerivativesPoint{T: r.FundingTime, V: val})
+	}
+	points = filterByDays(points, 7)
+	latest := 0.0
+	if len(points) > 0 {
+		latest = points[len(points)-1].V
+	}
+	return points, latest, nil
+}
+
+func (v *binanceVenue) OpenInterest(ctx context.Context, symbol string) ([]models.DerivativesPoint, float64, error) {
+	url := fmt.Sprintf("https://fapi.binance.com/futures/data/openInterestHist?symbol=%s&period=5m&limit=500", symbol)
+	var raw []struct {
+		SumOpenInterest string `json:"sumOpenInterest"`
+		Timestamp       int64  `json:"timestamp"`
+	}
+	if err := fetchWithBackoff(ctx, v.hc, url, &raw); err != nil {
+		return nil, 0, err
+	}
+	points := make([]models.DerivativesPoint, 0, len(raw))
+	for _, r := range raw {
+		val, err := strconv.ParseFloat(r.SumOpenInterest, 64)
+		if err != nil {
+			continue
+		}
+		points = append(points, models.DerivativesPoint{T: r.Timestamp, V: val})
+	}
+	latest := 0.0
+	if len(points) > 0 {
+		latest = points[len(points)-1].V
+	}
+	return points, latest, nil
+}
+
+// --- OKX ---
+
+type okxVenue struct{ hc *http.Client }
+
+func newOKXVenue(hc *http.Client) *okxVenue { return &okxVenue{hc: hc} }
+
+func (v *okxVenue) Name() string { return "okx" }
+
+func (v *okxVenue) NormalizeSymbol(symbol string) string { return okxInstID(symbol) }
+
+func (v *okxVenue) Funding(ctx context.Context, instID string) ([]models.DerivativesPoint, float64, error) {
+	url := fmt.Sprintf("https://www.okx.com/api/v5/public/funding-rate-history?instId=%s", instID)
+	var raw struct {
+		Data []struct {
+			FundingRate string `json:"fundingRate"`
+			Ts          string `json:"ts"`
+		} `json:"data"`
+	}
+	if err := fetchWithBackoff(ctx, v.hc, url, &raw); err != nil {
+		return nil, 0, err
+	}
+	points := make([]models.DerivativesPoint, 0, len(raw.Data))
+	for _, r := range raw.Data {
+		val, err := strconv.ParseFloat(r.FundingRate, 64)
+		if err != nil {
+			continue
+		}
+		ts, _ := strconv.ParseInt(r.Ts, 10, 64)
+		points = append(points, models.DerivativesPoint{T: ts, V: val})
+	}
+	points = filterByDays(points, 7)
+	latest := 0.0
+	if len(points) > 0 {
+		latest = points[0].V
+	}
+	return points, latest, nil
+}
+
+func (v *okxVenue) OpenInterest(ctx context.Context, instID string) ([]models.DerivativesPoint, float64, error) {
+	url := fmt.Sprintf("https://www.okx.com/api/v5/public/open-interest?instType=SWAP&instId=%s", instID)
+	var raw struct {
+		Data []struct {
+			OI string `json:"oi"`
+			Ts string `json:"ts"`
+		} `json:"data"`
+	}
+	if err := fetchWithBackoff(ctx, v.hc, url, &raw); err != nil {
+		return nil, 0, err
+	}
+	points := make([]models.DerivativesPoint, 0, len(raw.Data))
+	for _, r := range raw.Data {
+		val, err := strconv.ParseFloat(r.OI, 64)
+		if err != nil {
+			continue
+		}
+		ts, _ := strconv.ParseInt(r.Ts, 10, 64)
+		points = append(points, models.DerivativesPoint{T: ts, V: val})
+	}
+	latest := 0.0
+	if len(points) > 0 {
+		latest = points[len(points)-1].V
+	}
+	return points, latest, nil
+}
+
+func okxInstID(symbol string) string {
+	if strings.Contains(symbol, "-") {
+		if strings.HasSuffix(symbol, "-SWAP") {
+			return symbol
+		}
+		return symbol + "-SWAP"
+	}
+	if strings.HasSuffix(symbol, "USDT") {
+		base := strings.TrimSuffix(symbol, "USDT")
+		return fmt.Sprintf("%s-USDT-SWAP", base)
+	}
+	return symbol + "-USDT-SWAP"
+}
+
+// --- Bybit ---
+
+type bybitVenue struct{ hc *http.Client }
+
+func newBybitVenue(hc *http.Client) *bybitVenue { return &bybitVenue{hc: hc} }
+
+func (v *bybitVenue) Name() string { return "bybit" }
+
+func (v *bybitVenue) NormalizeSymbol(symbol string) string { return strings.ToUpper(symbol) }
+
+func (v *bybitVenue) Funding(ctx context.Context, symbol string) ([]models.DerivativesPoint, float64, error) {
+	url := fmt.Sprintf("https://api.bybit.com/v5/market/history-fund-rate?category=linear&symbol=%s&limit=200", symbol)
+	var raw struct {
+		Result struct {
+			List []struct {
+				FundingRate          string `json:"fundingRate"`
+				FundingRateTimestamp string `json:"fundingRateTimestamp"`
+			} `json:"list"`
+		} `json:"result"`
+	}
+	if err := fetchWithBackoff(ctx, v.hc, url, &raw); err != nil {
+		return nil, 0, err
+	}
+	points := make([]models.DerivativesPoint, 0, len(raw.Result.List))
+	for _, r := range raw.Result.List {
+		val, err := strconv.ParseFloat(r.FundingRate, 64)
+		if err != nil {
+			continue
+		}
+		ts, _ := strconv.ParseInt(r.FundingRateTimestamp, 10, 64)
+		points = append(points, models.DerivativesPoint{T: ts, V: val})
+	}
+	points = filterByDays(points, 7)
+	latest := 0.0
+	if len(points) > 0 {
+		latest = points[0].V
+	}
+	return points, latest, nil
+}
+
+func (v *bybitVenue) OpenInterest(ctx context.Context, symbol string) ([]models.DerivativesPoint, float64, error) {
+	url := fmt.Sprintf("https://api.bybit.com/v5/market/open-interest?category=linear&symbol=%s&intervalTime=5min&limit=50", symbol)
+	var raw struct {
+		Result struct {
+			List []struct {
+				OpenInterest string `json:"openInterest"`
+				Timestamp    string `json:"timestamp"`
+			} `json:"list"`
+		} `json:"result"`
+	}
+	if err := fetchWithBackoff(ctx, v.hc, url, &raw); err != nil {
+		return nil, 0, err
+	}
+	points := make([]models.DerivativesPoint, 0, len(raw.Result.List))
+	for _, r := range raw.Result.List {
+		val, err := strconv.ParseFloat(r.OpenInterest, 64)
+		if err != nil {
+			continue
+		}
+		ts, _ := strconv.ParseInt(r.Timestamp, 10, 64)
+		points = append(points, models.DerivativesPoint{T: ts, V: val})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].T < points[j].T })
+	latest := 0.0
+	if len(points) > 0 {
+		latest = points[len(points)-1].V
+	}
+	return points, latest, nil
+}
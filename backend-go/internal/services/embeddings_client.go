@@ -0,0 +1,120 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"macroquant-intel/backend-go/internal/config"
+	"macroquant-intel/backend-go/internal/metrics"
+)
+
+// HTTPEmbeddingClient embeds text batches via a configured embeddings
+// endpoint, caching each text's vector in Cache by its content hash so
+// repeat queries and repeat news items don't re-embed.
+type HTTPEmbeddingClient struct {
+	hc    *http.Client
+	cache Cache
+	ttl   time.Duration
+	url   string
+}
+
+// NewHTTPEmbeddingClient builds an HTTPEmbeddingClient from cfg. Callers
+// should only construct one when cfg.EmbeddingsURL is set.
+func NewHTTPEmbeddingClient(cfg config.Config, cache Cache) *HTTPEmbeddingClient {
+	return &HTTPEmbeddingClient{
+		hc:    &http.Client{Timeout: cfg.RequestTimeout},
+		cache: cache,
+		ttl:   cfg.CacheTTLEmbeddings,
+		url:   cfg.EmbeddingsURL,
+	}
+}
+
+type embeddingsRequest struct {
+	Input []string `json:"input"`
+}
+
+type embeddingsResponse struct {
+	Embeddings [][]float64 `json:"embeddings"`
+}
+
+// Embed returns one vector per text in texts, in order. Cached vectors are
+// served without a round trip; any texts not yet cached are embedded in a
+// single batched call and cached for ttl.
+func (c *HTTPEmbeddingClient) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	out := make([][]float64, len(texts))
+	missIdx := make([]int, 0, len(texts))
+	missTexts := make([]string, 0, len(texts))
+	for i, text := range texts {
+		if c.cache != nil {
+			if b, ok := c.cache.Get(ctx, embeddingCacheKey(text)); ok {
+				var vec []float64
+				if err := UnmarshalCache(b, &vec); err == nil {
+					out[i] = vec
+					continue
+				}
+			}
+		}
+		missIdx = append(missIdx, i)
+		missTexts = append(missTexts, text)
+	}
+	if len(missTexts) == 0 {
+		return out, nil
+	}
+
+	payload, err := json.Marshal(embeddingsRequest{Input: missTexts})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := c.hc.Do(req)
+	if err != nil {
+		metrics.UpstreamRequests.WithLabelValues("embeddings", "network").Inc()
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		metrics.UpstreamRequests.WithLabelValues("embeddings", embeddingsOutcome(res.StatusCode)).Inc()
+		return nil, fmt.Errorf("embeddings service: %s", res.Status)
+	}
+	var decoded embeddingsResponse
+	if err := json.NewDecoder(res.Body).Decode(&decoded); err != nil {
+		return nil, err
+	}
+	if len(decoded.Embeddings) != len(missTexts) {
+		return nil, fmt.Errorf("embeddings service: expected %d vectors, got %d", len(missTexts), len(decoded.Embeddings))
+	}
+	metrics.UpstreamRequests.WithLabelValues("embeddings", "ok").Inc()
+
+	for j, idx := range missIdx {
+		vec := decoded.Embeddings[j]
+		out[idx] = vec
+		if c.cache != nil {
+			if b, err := MarshalCache(vec); err == nil {
+				_ = c.cache.Set(ctx, embeddingCacheKey(missTexts[j]), b, c.ttl)
+			}
+		}
+	}
+	return out, nil
+}
+
+func embeddingCacheKey(text string) string {
+	sum := sha1.Sum([]byte(text))
+	return fmt.Sprintf("embed:v1:%s", hex.EncodeToString(sum[:10]))
+}
+
+func embeddingsOutcome(status int) string {
+	if status >= 500 {
+		return "upstream_5xx"
+	}
+	return "upstream_4xx"
+}
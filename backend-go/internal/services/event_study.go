@@ -0,0 +1,402 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"macroquant-intel/backend-go/internal/config"
+	"macroquant-intel/backend-go/internal/models"
+)
+
+// EventStudyInput is what EventStudy needs to compute a single asset's
+// reaction to an event: just enough to key the cache and fetch price
+// history around it. Handlers adapt whichever of NewsItem/EventItem they
+// have on hand into this.
+type EventStudyInput struct {
+	EventID        string
+	Asset          string
+	Benchmark      string
+	PublishedAtISO string
+}
+
+// eventStudyPostWindow is one of the Post reaction horizons EventStudy
+// fills in EventReactions.Post, keyed by Name.
+type eventStudyPostWindow struct {
+	Name string
+	Dur  time.Duration
+}
+
+var eventStudyPostWindows = []eventStudyPostWindow{
+	{"5m", 5 * time.Minute},
+	{"15m", 15 * time.Minute},
+	{"1h", time.Hour},
+	{"4h", 4 * time.Hour},
+}
+
+const (
+	eventStudyPreStart = 30 * time.Minute
+	eventStudyPreEnd   = 5 * time.Minute
+	eventStudyBetaBars = 60
+	eventStudyMaxResZ  = 5.0
+)
+
+// EventStudy computes pre/post event price reactions from raw minute
+// bars, so FlowPanel.EventStudy can be filled on the Go side when the
+// Python analytics service (which normally computes these) is degraded.
+// Results are cached per (event_id, asset) keyed by PublishedAtISO, since
+// the underlying bars don't change once the event's reaction window has
+// closed.
+type EventStudy struct {
+	prices       PriceProvider
+	cache        Cache
+	ttl          time.Duration
+	lookbackDays int
+	benchmark    string
+}
+
+func NewEventStudy(cfg config.Config, cache Cache, prices PriceProvider) *EventStudy {
+	return &EventStudy{
+		prices:       prices,
+		cache:        cache,
+		ttl:          cfg.CacheTTLIntelHard,
+		lookbackDays: cfg.EventStudyLookbackDays,
+		benchmark:    cfg.EventStudyBenchmark,
+	}
+}
+
+// Compute returns in.Asset's reaction to the event at in.PublishedAtISO.
+func (s *EventStudy) Compute(ctx context.Context, in EventStudyInput) (models.EventReactions, error) {
+	key := eventStudyCacheKey(in)
+	if s.cache != nil {
+		if b, ok := s.cache.Get(ctx, key); ok {
+			var cached models.EventReactions
+			if err := UnmarshalCache(b, &cached); err == nil {
+				return cached, nil
+			}
+		}
+	}
+
+	t, err := time.Parse(time.RFC3339, in.PublishedAtISO)
+	if err != nil {
+		return models.EventReactions{}, fmt.Errorf("event study: parse published_at: %w", err)
+	}
+	benchmark := in.Benchmark
+	if benchmark == "" {
+		benchmark = s.benchmark
+	}
+	lookbackDays := s.lookbackDays
+	if lookbackDays <= 0 {
+		lookbackDays = 20
+	}
+
+	from := t.Add(-time.Duration(lookbackDays+1) * 24 * time.Hour)
+	to := t.Add(4*time.Hour + 10*time.Minute)
+
+	assetBars, err := s.prices.Bars(ctx, in.Asset, from, to)
+	if err != nil {
+		return models.EventReactions{}, fmt.Errorf("event study: fetch %s bars: %w", in.Asset, err)
+	}
+	benchBars, err := s.prices.Bars(ctx, benchmark, from, to)
+	if err != nil {
+		return models.EventReactions{}, fmt.Errorf("event study: fetch %s bars: %w", benchmark, err)
+	}
+
+	reactions := models.EventReactions{
+		Post: make(map[string]models.ReactionWindow, len(eventStudyPostWindows)),
+	}
+	var missing []string
+
+	beta := rollingBeta(assetBars, benchBars, t, eventStudyBetaBars)
+
+	preFrom, preFromOK := priceAt(assetBars, t.Add(-eventStudyPreStart))
+	preTo, preToOK := priceAt(assetBars, t.Add(-eventStudyPreEnd))
+	if preFromOK && preToOK && preFrom != 0 {
+		preRet := preTo/preFrom - 1
+		reactions.Pre = &models.ReactionWindow{Ret: &preRet}
+		reactions.Pre30mRet = &preRet
+	} else {
+		missing = append(missing, "pre")
+	}
+
+	atEvent, atEventOK := priceAt(assetBars, t)
+	benchAtEvent, benchAtEventOK := priceAt(benchBars, t)
+
+	for _, w := range eventStudyPostWindows {
+		if !atEventOK || !benchAtEventOK {
+			missing = append(missing, "post_"+w.Name)
+			continue
+		}
+		assetPost, assetOK := priceAt(assetBars, t.Add(w.Dur))
+		benchPost, benchOK := priceAt(benchBars, t.Add(w.Dur))
+		if !assetOK || !benchOK || atEvent == 0 || benchAtEvent == 0 {
+			missing = append(missing, "post_"+w.Name)
+			continue
+		}
+		assetRet := assetPost/atEvent - 1
+		benchRet := benchPost/benchAtEvent - 1
+		residual := assetRet - beta*benchRet
+
+		baseline := residualBaseline(assetBars, benchBars, t, w.Dur, beta, lookbackDays)
+		z := standardize(residual, baseline)
+		ret := residual
+		reactions.Post[w.Name] = models.ReactionWindow{Ret: &ret, Z: &z}
+
+		if w.Name == "4h" {
+			post30, ok30 := priceAt(assetBars, t.Add(30*time.Minute))
+			if ok30 && atEvent != 0 {
+				post30Ret := post30/atEvent - 1
+				reactions.Post30mRet = &post30Ret
+			}
+		}
+	}
+
+	volWindow := 4 * time.Hour
+	volBaseline := volumeBaseline(assetBars, t, volWindow, lookbackDays)
+	volActual := volumeSum(assetBars, t, t.Add(volWindow))
+	reactions.VolumeZ = standardize(volActual, volBaseline)
+
+	reactions.SparkPre = closesInRange(assetBars, t.Add(-eventStudyPreStart), t)
+	reactions.SparkPost = closesInRange(assetBars, t, t.Add(4*time.Hour))
+
+	if len(missing) == 0 {
+		reactions.DataStatus = "ok"
+	} else if len(missing) < len(eventStudyPostWindows)+1 {
+		reactions.DataStatus = "partial"
+	} else {
+		reactions.DataStatus = "missing"
+	}
+	reactions.MissingFields = missing
+
+	if reactions.DataStatus != "missing" && s.cache != nil {
+		if b, err := MarshalCache(reactions); err == nil {
+			_ = s.cache.Set(ctx, key, b, s.ttl)
+		}
+	}
+	return reactions, nil
+}
+
+// CombineReactions builds the event-level CombinedReaction from every
+// asset's Post window z-scores: Severity is the largest standardized
+// residual seen across assets and windows, capped at +/-5 sigma, and Mode
+// is the sign consensus across those same z-scores.
+func CombineReactions(reactions map[string]models.EventReactions) *models.CombinedReaction {
+	var maxAbsZ float64
+	var bull, bear int
+	for _, r := range reactions {
+		for _, w := range r.Post {
+			if w.Z == nil {
+				continue
+			}
+			z := clamp(*w.Z, -eventStudyMaxResZ, eventStudyMaxResZ)
+			if math.Abs(z) > maxAbsZ {
+				maxAbsZ = math.Abs(z)
+			}
+			if z > 0 {
+				bull++
+			} else if z < 0 {
+				bear++
+			}
+		}
+	}
+	if bull == 0 && bear == 0 {
+		return nil
+	}
+	mode := "mixed"
+	if bull > bear {
+		mode = "bull"
+	} else if bear > bull {
+		mode = "bear"
+	}
+	return &models.CombinedReaction{Mode: mode, Severity: maxAbsZ}
+}
+
+func eventStudyCacheKey(in EventStudyInput) string {
+	return fmt.Sprintf("eventstudy:v1:%s:%s:%s", in.EventID, in.Asset, in.PublishedAtISO)
+}
+
+// priceAt returns the close of the latest bar at or before t.
+func priceAt(bars []Bar, t time.Time) (float64, bool) {
+	var best Bar
+	found := false
+	for _, b := range bars {
+		ts, err := time.Parse(time.RFC3339, b.TsISO)
+		if err != nil || ts.After(t) {
+			continue
+		}
+		if !found || ts.After(mustParse(best.TsISO)) {
+			best = b
+			found = true
+		}
+	}
+	if !found {
+		return 0, false
+	}
+	return best.Close, true
+}
+
+func mustParse(s string) time.Time {
+	ts, _ := time.Parse(time.RFC3339, s)
+	return ts
+}
+
+func volumeSum(bars []Bar, from, to time.Time) float64 {
+	var sum float64
+	for _, b := range bars {
+		ts, err := time.Parse(time.RFC3339, b.TsISO)
+		if err != nil || ts.Before(from) || ts.After(to) {
+			continue
+		}
+		sum += b.Volume
+	}
+	return sum
+}
+
+func closesInRange(bars []Bar, from, to time.Time) []float64 {
+	out := make([]float64, 0)
+	for _, b := range bars {
+		ts, err := time.Parse(time.RFC3339, b.TsISO)
+		if err != nil || ts.Before(from) || ts.After(to) {
+			continue
+		}
+		out = append(out, b.Close)
+	}
+	return out
+}
+
+// rollingBeta estimates asset's beta against benchmark from the minute
+// returns of the eventStudyBetaBars bars immediately before t, matched by
+// timestamp. It returns 1 (no adjustment) if there isn't enough matched
+// history to estimate one.
+func rollingBeta(assetBars, benchBars []Bar, t time.Time, lookbackBars int) float64 {
+	benchClose := make(map[string]float64, len(benchBars))
+	for _, b := range benchBars {
+		benchClose[b.TsISO] = b.Close
+	}
+
+	type pair struct {
+		assetRet float64
+		benchRet float64
+	}
+	var prevAsset, prevBench float64
+	havePrev := false
+	var pairs []pair
+	sorted := append([]Bar(nil), assetBars...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].TsISO < sorted[j].TsISO })
+	for _, b := range sorted {
+		ts, err := time.Parse(time.RFC3339, b.TsISO)
+		if err != nil || ts.After(t) {
+			continue
+		}
+		bc, ok := benchClose[b.TsISO]
+		if !ok {
+			continue
+		}
+		if havePrev && prevAsset != 0 && prevBench != 0 {
+			pairs = append(pairs, pair{
+				assetRet: b.Close/prevAsset - 1,
+				benchRet: bc/prevBench - 1,
+			})
+		}
+		prevAsset, prevBench = b.Close, bc
+		havePrev = true
+	}
+	if len(pairs) > lookbackBars {
+		pairs = pairs[len(pairs)-lookbackBars:]
+	}
+	if len(pairs) < 5 {
+		return 1
+	}
+
+	var meanA, meanB float64
+	for _, p := range pairs {
+		meanA += p.assetRet
+		meanB += p.benchRet
+	}
+	meanA /= float64(len(pairs))
+	meanB /= float64(len(pairs))
+
+	var cov, varB float64
+	for _, p := range pairs {
+		da := p.assetRet - meanA
+		db := p.benchRet - meanB
+		cov += da * db
+		varB += db * db
+	}
+	if varB == 0 {
+		return 1
+	}
+	return cov / varB
+}
+
+// residualBaseline computes the market-model residual for the same
+// (window duration, beta) at the same time-of-day on each of the prior
+// lookbackDays days, giving EventStudy something to standardize the
+// event-day residual against.
+func residualBaseline(assetBars, benchBars []Bar, t time.Time, window time.Duration, beta float64, lookbackDays int) []float64 {
+	out := make([]float64, 0, lookbackDays)
+	for d := 1; d <= lookbackDays; d++ {
+		day := t.Add(-time.Duration(d) * 24 * time.Hour)
+		base, baseOK := priceAt(assetBars, day)
+		benchBase, benchBaseOK := priceAt(benchBars, day)
+		if !baseOK || !benchBaseOK || base == 0 || benchBase == 0 {
+			continue
+		}
+		post, postOK := priceAt(assetBars, day.Add(window))
+		benchPost, benchPostOK := priceAt(benchBars, day.Add(window))
+		if !postOK || !benchPostOK {
+			continue
+		}
+		assetRet := post/base - 1
+		benchRet := benchPost/benchBase - 1
+		out = append(out, assetRet-beta*benchRet)
+	}
+	return out
+}
+
+func volumeBaseline(bars []Bar, t time.Time, window time.Duration, lookbackDays int) []float64 {
+	out := make([]float64, 0, lookbackDays)
+	for d := 1; d <= lookbackDays; d++ {
+		day := t.Add(-time.Duration(d) * 24 * time.Hour)
+		out = append(out, volumeSum(bars, day, day.Add(window)))
+	}
+	return out
+}
+
+// standardize returns (value-mean(baseline))/stdev(baseline), or 0 if
+// baseline doesn't have enough samples to estimate a stdev from, capped
+// at +/-eventStudyMaxResZ.
+func standardize(value float64, baseline []float64) float64 {
+	if len(baseline) < 3 {
+		return 0
+	}
+	var mean float64
+	for _, v := range baseline {
+		mean += v
+	}
+	mean /= float64(len(baseline))
+
+	var variance float64
+	for _, v := range baseline {
+		d := v - mean
+		variance += d * d
+	}
+	variance /= float64(len(baseline))
+	stdev := math.Sqrt(variance)
+	if stdev == 0 {
+		return 0
+	}
+	return clamp((value-mean)/stdev, -eventStudyMaxResZ, eventStudyMaxResZ)
+}
+
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
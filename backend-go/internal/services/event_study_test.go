@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"macroquant-intel/backend-go/internal/config"
+	"macroquant-intel/backend-go/internal/models"
+)
+
+type fakePriceProvider struct {
+	bars map[string][]Bar
+}
+
+func (f *fakePriceProvider) Bars(_ context.Context, symbol string, from, to time.Time) ([]Bar, error) {
+	out := make([]Bar, 0)
+	for _, b := range f.bars[symbol] {
+		ts, err := time.Parse(time.RFC3339, b.TsISO)
+		if err != nil || ts.Before(from) || ts.After(to) {
+			continue
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+// buildFlatHistory synthesizes lookbackDays+1 days of minute bars for an
+// asset, each day following the same shape (flat except the event day),
+// so residual/volume baselines have something stable to standardize
+// against. drift, when non-zero, adds an intraday slope to every
+// baseline day (d%5-2 so it averages out to ~0 across a 20-day lookback)
+// so those baselines have a non-zero stdev instead of being perfectly
+// flat; the event day (d==0) is untouched by it, since its shape comes
+// from eventDayDelta instead.
+func buildFlatHistory(t time.Time, lookbackDays int, price, volume, drift float64, eventDayDelta func(time.Time) (float64, float64)) []Bar {
+	var bars []Bar
+	for d := lookbackDays; d >= 0; d-- {
+		day := t.Add(-time.Duration(d) * 24 * time.Hour)
+		slope := drift * (float64(d%5) - 2)
+		for m := -60; m <= 240; m++ {
+			ts := day.Add(time.Duration(m) * time.Minute)
+			p, v := price+slope*float64(m)/240.0, volume+slope*100
+			if d == 0 {
+				p, v = price, volume
+				if eventDayDelta != nil {
+					p, v = eventDayDelta(ts)
+				}
+			}
+			bars = append(bars, Bar{TsISO: ts.UTC().Format(time.RFC3339), Close: p, Volume: v})
+		}
+	}
+	return bars
+}
+
+func TestEventStudyComputeFlatBaselineYieldsZeroZScores(t *testing.T) {
+	event := time.Date(2026, 1, 20, 15, 0, 0, 0, time.UTC)
+	assetBars := buildFlatHistory(event, 20, 100, 1000, 0, nil)
+	benchBars := buildFlatHistory(event, 20, 50, 1000, 0, nil)
+
+	es := NewEventStudy(config.Config{EventStudyLookbackDays: 20, EventStudyBenchmark: "SPY"}, nil, &fakePriceProvider{
+		bars: map[string][]Bar{"ACME": assetBars, "SPY": benchBars},
+	})
+
+	reactions, err := es.Compute(context.Background(), EventStudyInput{
+		EventID:        "evt1",
+		Asset:          "ACME",
+		PublishedAtISO: event.Format(time.RFC3339),
+	})
+	if err != nil {
+		t.Fatalf("compute: %v", err)
+	}
+	if reactions.DataStatus != "ok" {
+		t.Fatalf("expected ok data status, got %q (missing=%v)", reactions.DataStatus, reactions.MissingFields)
+	}
+	if reactions.VolumeZ != 0 {
+		t.Fatalf("expected flat volume baseline to z-score to 0, got %v", reactions.VolumeZ)
+	}
+	for name, w := range reactions.Post {
+		if w.Z == nil || *w.Z != 0 {
+			t.Fatalf("expected window %s z-score 0 on a flat series, got %v", name, w.Z)
+		}
+	}
+}
+
+func TestEventStudyComputeDetectsPostEventJump(t *testing.T) {
+	event := time.Date(2026, 1, 20, 15, 0, 0, 0, time.UTC)
+	jump := func(ts time.Time) (float64, float64) {
+		if !ts.After(event) {
+			return 100, 1000
+		}
+		return 110, 5000
+	}
+	assetBars := buildFlatHistory(event, 20, 100, 1000, 0.3, jump)
+	benchBars := buildFlatHistory(event, 20, 50, 1000, 0, nil)
+
+	es := NewEventStudy(config.Config{EventStudyLookbackDays: 20, EventStudyBenchmark: "SPY"}, nil, &fakePriceProvider{
+		bars: map[string][]Bar{"ACME": assetBars, "SPY": benchBars},
+	})
+
+	reactions, err := es.Compute(context.Background(), EventStudyInput{
+		EventID:        "evt2",
+		Asset:          "ACME",
+		PublishedAtISO: event.Format(time.RFC3339),
+	})
+	if err != nil {
+		t.Fatalf("compute: %v", err)
+	}
+	w, ok := reactions.Post["5m"]
+	if !ok || w.Ret == nil || *w.Ret <= 0 {
+		t.Fatalf("expected a positive 5m residual after the jump, got %+v", w)
+	}
+	if w.Z == nil || *w.Z <= 0 {
+		t.Fatalf("expected a positive 5m z-score after the jump, got %v", w.Z)
+	}
+	if reactions.VolumeZ <= 0 {
+		t.Fatalf("expected positive volume z-score after the volume spike, got %v", reactions.VolumeZ)
+	}
+}
+
+func TestEventStudyComputeCachesResult(t *testing.T) {
+	event := time.Date(2026, 1, 20, 15, 0, 0, 0, time.UTC)
+	assetBars := buildFlatHistory(event, 20, 100, 1000, 0, nil)
+	benchBars := buildFlatHistory(event, 20, 50, 1000, 0, nil)
+	provider := &fakePriceProvider{bars: map[string][]Bar{"ACME": assetBars, "SPY": benchBars}}
+
+	cache := NewMemoryCache(config.Config{MemoryCacheMaxEntries: 10, MemoryCacheSweepInterval: time.Minute})
+	es := NewEventStudy(config.Config{EventStudyLookbackDays: 20, EventStudyBenchmark: "SPY"}, cache, provider)
+
+	in := EventStudyInput{EventID: "evt3", Asset: "ACME", PublishedAtISO: event.Format(time.RFC3339)}
+	if _, err := es.Compute(context.Background(), in); err != nil {
+		t.Fatalf("compute: %v", err)
+	}
+
+	provider.bars = nil
+	if _, err := es.Compute(context.Background(), in); err != nil {
+		t.Fatalf("expected a cached result without re-fetching bars, got error: %v", err)
+	}
+}
+
+func TestCombineReactionsMajorityBullIsBull(t *testing.T) {
+	posZ := 2.0
+	negZ := -1.0
+	reactions := map[string]models.EventReactions{
+		"ACME": {Post: map[string]models.ReactionWindow{"5m": {Z: &posZ}, "15m": {Z: &posZ}}},
+		"FOO":  {Post: map[string]models.ReactionWindow{"5m": {Z: &negZ}}},
+	}
+	combined := CombineReactions(reactions)
+	if combined == nil || combined.Mode != "bull" {
+		t.Fatalf("expected bull mode, got %+v", combined)
+	}
+	if combined.Severity != 2.0 {
+		t.Fatalf("expected severity 2.0 (largest |z|), got %v", combined.Severity)
+	}
+}
+
+func TestCombineReactionsNoReactionsReturnsNil(t *testing.T) {
+	if combined := CombineReactions(map[string]models.EventReactions{}); combined != nil {
+		t.Fatalf("expected nil combined reaction for no data, got %+v", combined)
+	}
+}
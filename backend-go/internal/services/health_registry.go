@@ -0,0 +1,140 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"macroquant-intel/backend-go/internal/config"
+	"macroquant-intel/backend-go/internal/models"
+)
+
+// Error classes ProviderResult.ErrorClass should use. Snapshot reports a
+// source's last ErrorClass as its ProviderDebug.ErrorCode, so failures
+// carry a category alongside the raw error message.
+const (
+	ErrorClassTimeout     = "timeout"
+	ErrorClass5xx         = "5xx"
+	ErrorClass4xx         = "4xx"
+	ErrorClassParse       = "parse"
+	ErrorClassCircuitOpen = "circuit_open"
+)
+
+// ProviderResult is what a single upstream call reports to HealthRegistry.
+type ProviderResult struct {
+	Ok         bool
+	Latency    time.Duration
+	CacheHit   bool
+	ErrorClass string
+	ErrorMsg   string
+}
+
+type providerHealthEntry struct {
+	last     ProviderResult
+	lastGood time.Time
+}
+
+// HealthRegistry is the central place upstream clients (PythonClient, the
+// Redis Cache layer, PriceProvider, ...) record their call outcomes, so
+// DebugInfo.Providers and HealthResponse.DepsStatus reflect real latency
+// and error history instead of each client silently discarding it. It is
+// held as a singleton on API and handed to whatever clients API
+// constructs.
+type HealthRegistry struct {
+	mu            sync.Mutex
+	entries       map[string]*providerHealthEntry
+	degradedAfter time.Duration
+}
+
+func NewHealthRegistry(cfg config.Config) *HealthRegistry {
+	return &HealthRegistry{
+		entries:       make(map[string]*providerHealthEntry),
+		degradedAfter: cfg.ProviderDegradedAfter,
+	}
+}
+
+// Record stores r as source's most recent call outcome, updating its
+// last-known-good timestamp when r.Ok.
+func (h *HealthRegistry) Record(source string, r ProviderResult) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	e, ok := h.entries[source]
+	if !ok {
+		e = &providerHealthEntry{}
+		h.entries[source] = e
+	}
+	e.last = r
+	if r.Ok {
+		e.lastGood = time.Now()
+	}
+}
+
+// Snapshot returns every recorded source's ProviderDebug, sorted by
+// source name so the same registry state always renders the same JSON,
+// plus a one-line rollup suitable for DebugInfo.ProviderMetricsSummary.
+func (h *HealthRegistry) Snapshot() ([]models.ProviderDebug, string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sources := make([]string, 0, len(h.entries))
+	for source := range h.entries {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	out := make([]models.ProviderDebug, 0, len(sources))
+	var degraded []string
+	for _, source := range sources {
+		degradedMode, ageS := h.degradedLocked(source)
+		e := h.entries[source]
+		out = append(out, models.ProviderDebug{
+			Source:       source,
+			Ok:           e.last.Ok,
+			LatencyMs:    int(e.last.Latency / time.Millisecond),
+			CacheHit:     e.last.CacheHit,
+			ErrorCode:    e.last.ErrorClass,
+			ErrorMsg:     e.last.ErrorMsg,
+			DegradedMode: degradedMode,
+			LastGoodAgeS: ageS,
+		})
+		if degradedMode {
+			degraded = append(degraded, source)
+		}
+	}
+
+	summary := fmt.Sprintf("%d/%d providers healthy", len(sources)-len(degraded), len(sources))
+	if len(degraded) > 0 {
+		summary += fmt.Sprintf(" (degraded: %s)", strings.Join(degraded, ", "))
+	}
+	return out, summary
+}
+
+// Degraded reports whether any recorded source currently exceeds the
+// degraded-after threshold, for HealthResponse's overall degraded flag.
+func (h *HealthRegistry) Degraded() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for source := range h.entries {
+		if degraded, _ := h.degradedLocked(source); degraded {
+			return true
+		}
+	}
+	return false
+}
+
+// degradedLocked reports whether source is degraded and how many seconds
+// old its last-known-good result is. Callers must hold h.mu.
+func (h *HealthRegistry) degradedLocked(source string) (degraded bool, ageS int) {
+	e := h.entries[source]
+	if e == nil || e.lastGood.IsZero() {
+		return true, 0
+	}
+	age := time.Since(e.lastGood)
+	ageS = int(age.Seconds())
+	if h.degradedAfter > 0 && age > h.degradedAfter {
+		return true, ageS
+	}
+	return false, ageS
+}
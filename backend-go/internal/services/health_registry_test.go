@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"macroquant-intel/backend-go/internal/config"
+)
+
+func TestHealthRegistrySnapshotSortedAndSummarized(t *testing.T) {
+	h := NewHealthRegistry(config.Config{ProviderDegradedAfter: time.Minute})
+	h.Record("redis", ProviderResult{Ok: true, Latency: 5 * time.Millisecond})
+	h.Record("python_intel", ProviderResult{Ok: false, ErrorClass: ErrorClass5xx, ErrorMsg: "python intel: 503"})
+
+	providers, summary := h.Snapshot()
+	if len(providers) != 2 {
+		t.Fatalf("expected 2 providers, got %d", len(providers))
+	}
+	if providers[0].Source != "python_intel" || providers[1].Source != "redis" {
+		t.Fatalf("expected providers sorted by source, got %+v", providers)
+	}
+	if providers[0].DegradedMode != true || providers[0].ErrorCode != ErrorClass5xx {
+		t.Fatalf("expected python_intel degraded with error class %s, got %+v", ErrorClass5xx, providers[0])
+	}
+	if providers[1].DegradedMode {
+		t.Fatalf("expected redis not degraded right after a successful call, got %+v", providers[1])
+	}
+	if summary != "1/2 providers healthy (degraded: python_intel)" {
+		t.Fatalf("unexpected summary: %q", summary)
+	}
+}
+
+func TestHealthRegistryDegradedAfterThreshold(t *testing.T) {
+	h := NewHealthRegistry(config.Config{ProviderDegradedAfter: time.Minute})
+	if h.Degraded() {
+		t.Fatalf("expected an empty registry to not report degraded")
+	}
+
+	h.Record("redis", ProviderResult{Ok: true})
+	if h.Degraded() {
+		t.Fatalf("expected a fresh success to not be degraded")
+	}
+
+	h.entries["redis"].lastGood = time.Now().Add(-2 * time.Minute)
+	if !h.Degraded() {
+		t.Fatalf("expected a stale last-good timestamp past the threshold to be degraded")
+	}
+}
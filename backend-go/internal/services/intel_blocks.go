@@ -0,0 +1,60 @@
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+
+	"macroquant-intel/backend-go/internal/models"
+)
+
+// IntelBlockNames lists the top-level IntelResponse sections a streaming
+// client can track independently, in the order StreamIntel emits them on
+// first connect.
+var IntelBlockNames = []string{
+	"market", "leaders", "top_news", "event_feed", "flow",
+	"derivatives", "risk", "daily_equity_movers", "forecast",
+}
+
+// IntelBlocks returns resp's top-level sections keyed by the names in
+// IntelBlockNames, for hashing or for incremental SSE delivery.
+func IntelBlocks(resp models.IntelResponse) map[string]any {
+	return map[string]any{
+		"market":              resp.Market,
+		"leaders":             resp.Leaders,
+		"top_news":            resp.TopNews,
+		"event_feed":          resp.EventFeed,
+		"flow":                resp.Flow,
+		"derivatives":         resp.Derivatives,
+		"risk":                resp.Risk,
+		"daily_equity_movers": resp.DailyMovers,
+		"forecast":            resp.Forecast,
+	}
+}
+
+// intelBlockHashes hashes each block of resp independently, so a
+// streaming client can tell which ones changed between two snapshots
+// without comparing full payloads.
+func intelBlockHashes(resp models.IntelResponse) map[string]string {
+	blocks := IntelBlocks(resp)
+	out := make(map[string]string, len(IntelBlockNames))
+	for _, name := range IntelBlockNames {
+		b, err := json.Marshal(blocks[name])
+		if err != nil {
+			continue
+		}
+		sum := sha1.Sum(b)
+		out[name] = hex.EncodeToString(sum[:8])
+	}
+	return out
+}
+
+// intelEtag folds a snapshot's block hashes into a single identifier for
+// the response as a whole.
+func intelEtag(hashes map[string]string) string {
+	sum := sha1.New()
+	for _, name := range IntelBlockNames {
+		sum.Write([]byte(hashes[name]))
+	}
+	return hex.EncodeToString(sum.Sum(nil)[:8])
+}
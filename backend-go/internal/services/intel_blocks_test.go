@@ -0,0 +1,44 @@
+package services
+
+import (
+	"testing"
+
+	"macroquant-intel/backend-go/internal/models"
+)
+
+func TestIntelBlockHashesAreStableForIdenticalInput(t *testing.T) {
+	resp := models.IntelResponse{Flow: models.FlowPanel{FlowScore: 5}}
+	a := intelBlockHashes(resp)
+	b := intelBlockHashes(resp)
+	if a["flow"] != b["flow"] {
+		t.Fatalf("expected stable hash for identical input, got %q and %q", a["flow"], b["flow"])
+	}
+}
+
+func TestIntelBlockHashesChangeOnlyForTheBlockThatChanged(t *testing.T) {
+	before := models.IntelResponse{
+		Flow: models.FlowPanel{FlowScore: 5},
+		Risk: models.RiskPanel{RSI: 50},
+	}
+	after := before
+	after.Flow = models.FlowPanel{FlowScore: 6}
+
+	hBefore := intelBlockHashes(before)
+	hAfter := intelBlockHashes(after)
+
+	if hBefore["flow"] == hAfter["flow"] {
+		t.Fatal("expected the flow hash to change when FlowScore changed")
+	}
+	if hBefore["risk"] != hAfter["risk"] {
+		t.Fatal("expected the risk hash to stay stable when only flow changed")
+	}
+}
+
+func TestIntelEtagChangesWhenAnyBlockHashChanges(t *testing.T) {
+	h1 := map[string]string{"flow": "aaaa", "risk": "bbbb"}
+	h2 := map[string]string{"flow": "cccc", "risk": "bbbb"}
+
+	if intelEtag(h1) == intelEtag(h2) {
+		t.Fatal("expected the etag to change when a block hash changes")
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha1"
 	"encoding/hex"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 
 	"macroquant-intel/backend-go/internal/config"
 	"macroquant-intel/backend-go/internal/models"
+	"macroquant-intel/backend-go/internal/services/broker"
 )
 
 type SnapshotMeta struct {
@@ -22,10 +24,16 @@ type SnapshotMeta struct {
 }
 
 type IntelSnapshot struct {
+	Seq  uint64
 	Resp models.IntelResponse
 	Meta SnapshotMeta
 }
 
+// intelRingSize is how many recent snapshots each topic buffers, so a
+// client reconnecting with a Last-Event-ID can be replayed the updates it
+// missed instead of silently skipping ahead.
+const intelRingSize = 32
+
 type intelCacheEntry struct {
 	FetchedAt string               `json:"fetched_at"`
 	Resp      models.IntelResponse `json:"resp"`
@@ -36,11 +44,36 @@ type intelTopic struct {
 	cancel   context.CancelFunc
 	interval time.Duration
 	last     *IntelSnapshot
+	ring     []IntelSnapshot
+	seq      uint64
+}
+
+// since returns the buffered snapshots newer than afterSeq. ok is false
+// when afterSeq is older than the ring buffer's oldest entry, meaning some
+// snapshots in between were dropped and the caller should tell the client
+// to do a full refetch instead of trusting a partial replay.
+func (t *intelTopic) since(afterSeq uint64) ([]IntelSnapshot, bool) {
+	if len(t.ring) == 0 {
+		return nil, afterSeq == 0
+	}
+	if afterSeq < t.ring[0].Seq-1 {
+		return nil, false
+	}
+	out := make([]IntelSnapshot, 0, len(t.ring))
+	for _, snap := range t.ring {
+		if snap.Seq > afterSeq {
+			out = append(out, snap)
+		}
+	}
+	return out, true
 }
 
 type IntelService struct {
 	cfg        config.Config
 	cache      Cache
+	broker     Broker
+	bus        broker.Broker
+	instanceID string
 	py         *PythonClient
 	mu         sync.Mutex
 	topics     map[string]*intelTopic
@@ -48,18 +81,51 @@ type IntelService struct {
 }
 
 func NewIntelService(cfg config.Config, cache Cache, py *PythonClient) *IntelService {
+	leaseBroker, _ := cache.(Broker)
+	if nc, ok := cache.(*nearCache); ok {
+		// The lease and pub/sub live on the RedisCache behind the near-cache.
+		leaseBroker = nc.remote
+	}
 	return &IntelService{
 		cfg:        cfg,
 		cache:      cache,
+		broker:     leaseBroker,
+		bus:        broker.New(cfg),
+		instanceID: randomInstanceID(),
 		py:         py,
 		topics:     make(map[string]*intelTopic),
 		refreshing: make(map[string]bool),
 	}
 }
 
-func (s *IntelService) Subscribe(ctx context.Context, timeframe string, newsTimespan string, watch []string, interval time.Duration) (<-chan IntelSnapshot, func()) {
+// NATSStatus reports whether NATS is configured for the cross-instance
+// intel fan-out and, if so, whether it's currently reachable, for
+// Health's DepsStatus.
+func (s *IntelService) NATSStatus(ctx context.Context) (configured bool, err error) {
+	if s.cfg.NATSURL == "" {
+		return false, nil
+	}
+	return true, s.bus.Healthy(ctx)
+}
+
+func randomInstanceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("pid-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// Subscribe attaches to the topic for (timeframe, newsTimespan, watch),
+// starting it if this is the first subscriber. lastEventID is the seq the
+// caller last saw (0 if none): when non-zero, replay holds the buffered
+// snapshots newer than it and ok reports whether the ring buffer still
+// reached back that far; when zero, replay holds just the latest known
+// snapshot (if any) so a fresh connection isn't left waiting a full
+// interval for its first update.
+func (s *IntelService) Subscribe(ctx context.Context, timeframe string, newsTimespan string, watch []string, interval time.Duration, lastEventID uint64) (ch <-chan IntelSnapshot, unsubscribe func(), replay []IntelSnapshot, ok bool) {
 	key := intelCacheKey(timeframe, newsTimespan, watch)
-	ch := make(chan IntelSnapshot, 1)
+	out := make(chan IntelSnapshot, 1)
 	var once sync.Once
 
 	s.mu.Lock()
@@ -70,22 +136,21 @@ func (s *IntelService) Subscribe(ctx context.Context, timeframe string, newsTime
 		s.topics[key] = topic
 		go s.runTopic(bgCtx, key, timeframe, newsTimespan, watch, interval)
 	}
-	topic.subs[ch] = struct{}{}
-	last := topic.last
-	s.mu.Unlock()
+	topic.subs[out] = struct{}{}
 
-	if last != nil {
-		select {
-		case ch <- *last:
-		default:
-		}
+	ok = true
+	if lastEventID > 0 {
+		replay, ok = topic.since(lastEventID)
+	} else if topic.last != nil {
+		replay = []IntelSnapshot{*topic.last}
 	}
+	s.mu.Unlock()
 
-	unsubscribe := func() {
+	unsubscribeFn := func() {
 		once.Do(func() {
 			s.mu.Lock()
 			if t := s.topics[key]; t != nil {
-				delete(t.subs, ch)
+				delete(t.subs, out)
 				empty := len(t.subs) == 0
 				if empty {
 					t.cancel()
@@ -93,16 +158,16 @@ func (s *IntelService) Subscribe(ctx context.Context, timeframe string, newsTime
 				}
 			}
 			s.mu.Unlock()
-			close(ch)
+			close(out)
 		})
 	}
 
 	go func() {
 		<-ctx.Done()
-		unsubscribe()
+		unsubscribeFn()
 	}()
 
-	return ch, unsubscribe
+	return out, unsubscribeFn, replay, ok
 }
 
 func (s *IntelService) GetSnapshot(ctx context.Context, timeframe string, newsTimespan string, watch []string) (models.IntelResponse, SnapshotMeta, error) {
@@ -147,10 +212,67 @@ func (s *IntelService) GetSnapshot(ctx context.Context, timeframe string, newsTi
 }
 
 func (s *IntelService) runTopic(ctx context.Context, key string, timeframe string, newsTimespan string, watch []string, interval time.Duration) {
+	if s.broker == nil {
+		s.runTopicLocal(ctx, key, timeframe, newsTimespan, watch, interval)
+		return
+	}
+	s.runTopicDistributed(ctx, key, timeframe, newsTimespan, watch, interval)
+}
+
+// runTopicLocal is the single-replica path: this instance always fetches
+// and publishes to its own in-process subscribers.
+func (s *IntelService) runTopicLocal(ctx context.Context, key string, timeframe string, newsTimespan string, watch []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	produce := func() {
+		reqCtx, cancel := context.WithTimeout(context.Background(), s.intelRequestTimeout())
+		defer cancel()
+		resp, meta, err := s.GetSnapshot(reqCtx, timeframe, newsTimespan, watch)
+		if err != nil && resp.TsISO == "" {
+			return
+		}
+		s.publish(key, IntelSnapshot{Resp: resp, Meta: meta})
+	}
+
+	produce()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			produce()
+		}
+	}
+}
+
+// runTopicDistributed elects one replica per cache key as the producer via
+// a Redis SET NX PX lease, renewed each tick. The lease holder calls the
+// Python analytics service and publishes the result to the shared
+// intel.v1.<sha> subject on s.bus (NATS if configured, in-process
+// otherwise); every other replica (leader or not) subscribes to that
+// subject and feeds received snapshots to its own local subscribers, so
+// only one replica ever calls out to Python per key.
+func (s *IntelService) runTopicDistributed(ctx context.Context, key string, timeframe string, newsTimespan string, watch []string, interval time.Duration) {
+	leaseKey := "intel:lease:" + key
+	subject := intelSubject(key)
+
+	remote, unsubscribe := s.bus.Subscribe(ctx, subject)
+	defer unsubscribe()
+
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	publish := func() {
+	isLeader := false
+	leadOrRenew := func() {
+		ok, err := s.broker.AcquireOrRenewLease(ctx, leaseKey, s.instanceID, 2*interval)
+		if err != nil {
+			return
+		}
+		isLeader = ok
+	}
+
+	produce := func() {
 		reqCtx, cancel := context.WithTimeout(context.Background(), s.intelRequestTimeout())
 		defer cancel()
 		resp, meta, err := s.GetSnapshot(reqCtx, timeframe, newsTimespan, watch)
@@ -159,15 +281,36 @@ func (s *IntelService) runTopic(ctx context.Context, key string, timeframe strin
 		}
 		snap := IntelSnapshot{Resp: resp, Meta: meta}
 		s.publish(key, snap)
+		if b, mErr := MarshalCache(snap); mErr == nil {
+			_ = s.bus.Publish(reqCtx, subject, b)
+		}
+	}
+
+	leadOrRenew()
+	if isLeader {
+		produce()
 	}
 
-	publish()
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			publish()
+			leadOrRenew()
+			if isLeader {
+				produce()
+			}
+		case raw, ok := <-remote:
+			if !ok {
+				return
+			}
+			if isLeader {
+				continue
+			}
+			var snap IntelSnapshot
+			if err := UnmarshalCache(raw, &snap); err == nil {
+				s.publish(key, snap)
+			}
 		}
 	}
 }
@@ -176,7 +319,13 @@ func (s *IntelService) publish(key string, snap IntelSnapshot) {
 	s.mu.Lock()
 	topic := s.topics[key]
 	if topic != nil {
+		topic.seq++
+		snap.Seq = topic.seq
 		topic.last = &snap
+		topic.ring = append(topic.ring, snap)
+		if len(topic.ring) > intelRingSize {
+			topic.ring = topic.ring[len(topic.ring)-intelRingSize:]
+		}
 		for ch := range topic.subs {
 			select {
 			case ch <- snap:
@@ -223,6 +372,9 @@ func (s *IntelService) fetchAndCache(ctx context.Context, key string, timeframe
 		return resp, SnapshotMeta{Source: "error", Err: err.Error()}, err
 	}
 
+	resp.BlockHashes = intelBlockHashes(resp)
+	resp.Etag = intelEtag(resp.BlockHashes)
+
 	now := time.Now().UTC()
 	if s.cache != nil && hasCacheableIntel(resp) {
 		entry := intelCacheEntry{
@@ -296,3 +448,11 @@ func intelCacheKey(timeframe string, newsTimespan string, watch []string) string
 	sum := sha1.Sum([]byte(strings.Join(safeWatch, ",")))
 	return fmt.Sprintf("intel:v2:%s:%s:%s", timeframe, newsTimespan, hex.EncodeToString(sum[:8]))
 }
+
+// intelSubject derives the bus subject a key's distributed producer
+// publishes to from its cache key, so every replica agrees on the name
+// without having to share the cache key string verbatim.
+func intelSubject(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return fmt.Sprintf("intel.v1.%s", hex.EncodeToString(sum[:8]))
+}
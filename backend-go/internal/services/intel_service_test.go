@@ -28,3 +28,21 @@ func TestIntelRequestTimeoutPrefersIntelTimeout(t *testing.T) {
 		t.Fatalf("expected fallback request timeout 12s, got %v", got)
 	}
 }
+
+func TestIntelTopicSinceReplaysOnlyNewerSnapshots(t *testing.T) {
+	topic := &intelTopic{ring: []IntelSnapshot{{Seq: 3}, {Seq: 4}, {Seq: 5}}}
+	got, ok := topic.since(3)
+	if !ok {
+		t.Fatal("expected ok for a cursor within the buffered window")
+	}
+	if len(got) != 2 || got[0].Seq != 4 || got[1].Seq != 5 {
+		t.Fatalf("expected snapshots 4 and 5, got %+v", got)
+	}
+}
+
+func TestIntelTopicSinceRejectsCursorOlderThanBuffer(t *testing.T) {
+	topic := &intelTopic{ring: []IntelSnapshot{{Seq: 10}, {Seq: 11}}}
+	if _, ok := topic.since(5); ok {
+		t.Fatal("expected cursor older than the buffered window to be rejected")
+	}
+}
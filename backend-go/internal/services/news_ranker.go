@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"macroquant-intel/backend-go/internal/models"
+)
+
+// NewsRanker re-orders a set of already-filtered NewsItem candidates by
+// relevance to query. Implementations must be stable: items that score
+// equally keep their relative order, so a query with no signal (e.g. no
+// recognized tokens) is a no-op rather than a shuffle.
+type NewsRanker interface {
+	Rank(ctx context.Context, query string, items []models.NewsItem) ([]models.NewsItem, error)
+}
+
+var newsTokenPattern = regexp.MustCompile(`[a-z0-9]+`)
+
+func newsTokens(s string) []string {
+	return newsTokenPattern.FindAllString(strings.ToLower(s), -1)
+}
+
+func newsDoc(item models.NewsItem) string {
+	return item.Title + " " + item.ShortSummary + " " + strings.Join(item.Tags, " ")
+}
+
+// BM25Ranker scores candidates against a query using Okapi BM25, with IDF
+// computed from the candidate set itself rather than a precomputed corpus
+// statistic: News handler candidates are already a small, pre-filtered
+// slice, so per-request IDF is cheap and needs no corpus to maintain.
+type BM25Ranker struct {
+	K1 float64
+	B  float64
+}
+
+// NewBM25Ranker builds a BM25Ranker with the given k1 (term-frequency
+// saturation) and b (document-length normalization) parameters.
+func NewBM25Ranker(k1, b float64) *BM25Ranker {
+	return &BM25Ranker{K1: k1, B: b}
+}
+
+func (r *BM25Ranker) Rank(_ context.Context, query string, items []models.NewsItem) ([]models.NewsItem, error) {
+	scores := bm25Scores(r.K1, r.B, query, items)
+	return sortByScore(items, scores).items, nil
+}
+
+// bm25Scores returns a BM25 score for each item in items, in the same
+// order, against query's tokens. A zero score means no query term matched
+// the item at all.
+func bm25Scores(k1, b float64, query string, items []models.NewsItem) []float64 {
+	scores := make([]float64, len(items))
+	queryTokens := newsTokens(query)
+	if len(queryTokens) == 0 || len(items) == 0 {
+		return scores
+	}
+
+	docTokens := make([][]string, len(items))
+	totalLen := 0
+	for i, it := range items {
+		docTokens[i] = newsTokens(newsDoc(it))
+		totalLen += len(docTokens[i])
+	}
+	avgLen := float64(totalLen) / float64(len(items))
+	if avgLen == 0 {
+		avgLen = 1
+	}
+
+	queryTermSet := make(map[string]struct{}, len(queryTokens))
+	for _, t := range queryTokens {
+		queryTermSet[t] = struct{}{}
+	}
+	docFreq := make(map[string]int, len(queryTermSet))
+	for term := range queryTermSet {
+		for _, toks := range docTokens {
+			if tokenCount(toks, term) > 0 {
+				docFreq[term]++
+			}
+		}
+	}
+
+	n := float64(len(items))
+	for i, toks := range docTokens {
+		tf := make(map[string]int, len(toks))
+		for _, t := range toks {
+			tf[t]++
+		}
+		var score float64
+		for term := range queryTermSet {
+			f := float64(tf[term])
+			if f == 0 {
+				continue
+			}
+			df := float64(docFreq[term])
+			idf := math.Log((n-df+0.5)/(df+0.5) + 1)
+			denom := f + k1*(1-b+b*float64(len(toks))/avgLen)
+			score += idf * f * (k1 + 1) / denom
+		}
+		scores[i] = score
+	}
+	return scores
+}
+
+func tokenCount(toks []string, term string) int {
+	n := 0
+	for _, t := range toks {
+		if t == term {
+			n++
+		}
+	}
+	return n
+}
+
+func maxFloat(vals []float64) float64 {
+	m := 0.0
+	for _, v := range vals {
+		if v > m {
+			m = v
+		}
+	}
+	return m
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// EmbeddingClient embeds a batch of texts in one call, used by
+// EmbeddingReranker to fetch the query's and each candidate's vector.
+type EmbeddingClient interface {
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+// EmbeddingReranker re-scores the top BM25 hits by cosine similarity
+// between the query's embedding and each candidate's, blending the two so
+// semantic matches BM25's token overlap misses can still surface, without
+// discarding BM25's cheaper first pass over the full candidate set.
+type EmbeddingReranker struct {
+	bm25   *BM25Ranker
+	embed  EmbeddingClient
+	topN   int
+	weight float64 // weight on the embedding cosine score; BM25 gets 1-weight.
+}
+
+// NewEmbeddingReranker builds an EmbeddingReranker. topN bounds how many
+// of the BM25-ranked candidates get embedded and re-scored; the remainder
+// keep their BM25 order and are appended after. embed may be nil (e.g. no
+// embeddings endpoint configured), in which case Rank degrades to plain
+// BM25 ranking.
+func NewEmbeddingReranker(bm25 *BM25Ranker, embed EmbeddingClient, topN int, weight float64) *EmbeddingReranker {
+	return &EmbeddingReranker{bm25: bm25, embed: embed, topN: topN, weight: weight}
+}
+
+func (r *EmbeddingReranker) Rank(ctx context.Context, query string, items []models.NewsItem) ([]models.NewsItem, error) {
+	scores := bm25Scores(r.bm25.K1, r.bm25.B, query, items)
+	ranked := sortByScore(items, scores)
+	if r.embed == nil || len(ranked.items) == 0 {
+		return ranked.items, nil
+	}
+
+	topN := r.topN
+	if topN <= 0 || topN > len(ranked.items) {
+		topN = len(ranked.items)
+	}
+	head := ranked.items[:topN]
+	headScores := ranked.scores[:topN]
+	tail := ranked.items[topN:]
+
+	texts := make([]string, 0, len(head)+1)
+	texts = append(texts, query)
+	for _, it := range head {
+		texts = append(texts, newsDoc(it))
+	}
+	vectors, err := r.embed.Embed(ctx, texts)
+	if err != nil || len(vectors) != len(texts) || vectors[0] == nil {
+		return ranked.items, nil
+	}
+	queryVec := vectors[0]
+	maxBM25 := maxFloat(headScores)
+
+	blendScores := make([]float64, len(head))
+	for i := range head {
+		cos := cosineSimilarity(queryVec, vectors[i+1])
+		normBM25 := 0.0
+		if maxBM25 > 0 {
+			normBM25 = headScores[i] / maxBM25
+		}
+		blendScores[i] = r.weight*cos + (1-r.weight)*normBM25
+	}
+	blended := sortByScore(head, blendScores)
+
+	out := make([]models.NewsItem, 0, len(items))
+	out = append(out, blended.items...)
+	out = append(out, tail...)
+	return out, nil
+}
+
+type scoredNews struct {
+	items  []models.NewsItem
+	scores []float64
+}
+
+// sortByScore pairs items with their parallel scores slice and returns
+// both reordered descending by score, stably.
+func sortByScore(items []models.NewsItem, scores []float64) scoredNews {
+	idx := make([]int, len(items))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return scores[idx[i]] > scores[idx[j]] })
+
+	out := scoredNews{
+		items:  make([]models.NewsItem, len(items)),
+		scores: make([]float64, len(items)),
+	}
+	for i, j := range idx {
+		out.items[i] = items[j]
+		out.scores[i] = scores[j]
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"macroquant-intel/backend-go/internal/models"
+)
+
+func TestBM25RankerOrdersByRelevanceNotPosition(t *testing.T) {
+	items := []models.NewsItem{
+		{Title: "Oil output cut", ShortSummary: "OPEC trims supply", Tags: []string{"Energy"}},
+		{Title: "Fed sees inflation easing", ShortSummary: "CPI slowed, Fed watches inflation closely", Tags: []string{"Macro", "Fed"}},
+		{Title: "Chipmaker earnings beat", ShortSummary: "Tech rally continues", Tags: []string{"Tech"}},
+	}
+
+	ranker := NewBM25Ranker(1.5, 0.75)
+	ranked, err := ranker.Rank(context.Background(), "fed inflation", items)
+	if err != nil {
+		t.Fatalf("rank: %v", err)
+	}
+	if ranked[0].Title != "Fed sees inflation easing" {
+		t.Fatalf("expected the Fed item ranked first, got %q", ranked[0].Title)
+	}
+}
+
+func TestBM25RankerIsNoOpWithoutQueryTokens(t *testing.T) {
+	items := []models.NewsItem{
+		{Title: "A"},
+		{Title: "B"},
+		{Title: "C"},
+	}
+	ranker := NewBM25Ranker(1.5, 0.75)
+	ranked, err := ranker.Rank(context.Background(), "", items)
+	if err != nil {
+		t.Fatalf("rank: %v", err)
+	}
+	for i, it := range ranked {
+		if it.Title != items[i].Title {
+			t.Fatalf("expected original order preserved, got %v", ranked)
+		}
+	}
+}
+
+type fakeEmbedClient struct {
+	vectors map[string][]float64
+}
+
+func (f *fakeEmbedClient) Embed(_ context.Context, texts []string) ([][]float64, error) {
+	out := make([][]float64, len(texts))
+	for i, t := range texts {
+		out[i] = f.vectors[t]
+	}
+	return out, nil
+}
+
+func TestEmbeddingRerankerBlendsTowardsSemanticMatch(t *testing.T) {
+	items := []models.NewsItem{
+		{Title: "Rate decision looms", ShortSummary: "central bank meeting next week"},
+		{Title: "Quarterly earnings beat expectations", ShortSummary: "shares rally on results"},
+	}
+	query := "interest rate policy"
+
+	embed := &fakeEmbedClient{vectors: map[string][]float64{
+		query:             {1, 0},
+		newsDoc(items[0]): {1, 0},
+		newsDoc(items[1]): {0, 1},
+	}}
+
+	bm25 := NewBM25Ranker(1.5, 0.75)
+	reranker := NewEmbeddingReranker(bm25, embed, 10, 0.9)
+	ranked, err := reranker.Rank(context.Background(), query, items)
+	if err != nil {
+		t.Fatalf("rank: %v", err)
+	}
+	if ranked[0].Title != "Rate decision looms" {
+		t.Fatalf("expected the semantically closer item first, got %q", ranked[0].Title)
+	}
+}
+
+func TestEmbeddingRerankerDegradesToBM25WithoutClient(t *testing.T) {
+	items := []models.NewsItem{
+		{Title: "Fed sees inflation easing", ShortSummary: "CPI slowed"},
+		{Title: "Oil output cut", ShortSummary: "OPEC trims supply"},
+	}
+	bm25 := NewBM25Ranker(1.5, 0.75)
+	reranker := NewEmbeddingReranker(bm25, nil, 10, 0.5)
+
+	ranked, err := reranker.Rank(context.Background(), "fed inflation", items)
+	if err != nil {
+		t.Fatalf("rank: %v", err)
+	}
+	if ranked[0].Title != "Fed sees inflation easing" {
+		t.Fatalf("expected BM25 order preserved without an embedding client, got %q", ranked[0].Title)
+	}
+}
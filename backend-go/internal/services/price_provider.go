@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"macroquant-intel/backend-go/internal/config"
+	"macroquant-intel/backend-go/internal/metrics"
+)
+
+// Bar is one minute-bar OHLCV sample.
+type Bar struct {
+	TsISO  string  `json:"tsISO"`
+	Open   float64 `json:"open"`
+	High   float64 `json:"high"`
+	Low    float64 `json:"low"`
+	Close  float64 `json:"close"`
+	Volume float64 `json:"volume"`
+}
+
+// PriceProvider fetches minute-bar price series for an asset over a time
+// range, sorted ascending by TsISO. It is the input EventStudy needs and
+// the quotes/derivatives side doesn't provide, since those only carry the
+// latest snapshot rather than history.
+type PriceProvider interface {
+	Bars(ctx context.Context, symbol string, from, to time.Time) ([]Bar, error)
+}
+
+// HTTPPriceProvider fetches minute bars from the Python analytics
+// service, caching each (symbol, from, to) request in Cache since event
+// study history doesn't change once the bars have closed.
+type HTTPPriceProvider struct {
+	hc      *http.Client
+	cache   Cache
+	ttl     time.Duration
+	baseURL string
+	health  *HealthRegistry
+}
+
+func NewHTTPPriceProvider(cfg config.Config, cache Cache) *HTTPPriceProvider {
+	return &HTTPPriceProvider{
+		hc:      &http.Client{Timeout: cfg.RequestTimeout},
+		cache:   cache,
+		ttl:     cfg.CacheTTLBars,
+		baseURL: cfg.PyBaseURL,
+	}
+}
+
+// SetHealthRegistry attaches h so Bars records its outcomes under the
+// "prices" source. Optional: an HTTPPriceProvider with no registry
+// attached just skips recording.
+func (c *HTTPPriceProvider) SetHealthRegistry(h *HealthRegistry) {
+	c.health = h
+}
+
+type barsResponse struct {
+	Bars []Bar `json:"bars"`
+}
+
+// Bars returns symbol's minute bars between from and to, ascending.
+func (c *HTTPPriceProvider) Bars(ctx context.Context, symbol string, from, to time.Time) ([]Bar, error) {
+	start := time.Now()
+	key := barsCacheKey(symbol, from, to)
+	if c.cache != nil {
+		if b, ok := c.cache.Get(ctx, key); ok {
+			var cached []Bar
+			if err := UnmarshalCache(b, &cached); err == nil {
+				c.recordResult(start, nil, true)
+				return cached, nil
+			}
+		}
+	}
+
+	base := strings.TrimRight(c.baseURL, "/")
+	url := fmt.Sprintf("%s/prices/bars", base)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Set("symbol", symbol)
+	q.Set("from", from.UTC().Format(time.RFC3339))
+	q.Set("to", to.UTC().Format(time.RFC3339))
+	req.URL.RawQuery = q.Encode()
+
+	res, err := c.hc.Do(req)
+	if err != nil {
+		metrics.UpstreamRequests.WithLabelValues("bars", "network").Inc()
+		c.recordResult(start, err, false)
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		metrics.UpstreamRequests.WithLabelValues("bars", barsOutcome(res.StatusCode)).Inc()
+		err := fmt.Errorf("bars service: %s", res.Status)
+		c.recordResult(start, priceProviderStatusError{status: res.StatusCode, err: err}, false)
+		return nil, err
+	}
+	var decoded barsResponse
+	if err := json.NewDecoder(res.Body).Decode(&decoded); err != nil {
+		c.recordResult(start, pythonParseError{err}, false)
+		return nil, err
+	}
+	sort.Slice(decoded.Bars, func(i, j int) bool { return decoded.Bars[i].TsISO < decoded.Bars[j].TsISO })
+
+	metrics.UpstreamRequests.WithLabelValues("bars", "ok").Inc()
+	c.recordResult(start, nil, false)
+	if c.cache != nil {
+		if b, err := MarshalCache(decoded.Bars); err == nil {
+			_ = c.cache.Set(ctx, key, b, c.ttl)
+		}
+	}
+	return decoded.Bars, nil
+}
+
+// priceProviderStatusError marks a Bars non-2xx response so
+// priceProviderErrorClass can tell 4xx from 5xx.
+type priceProviderStatusError struct {
+	status int
+	err    error
+}
+
+func (e priceProviderStatusError) Error() string { return e.err.Error() }
+
+// recordResult reports a Bars outcome to the attached HealthRegistry, if
+// any.
+func (c *HTTPPriceProvider) recordResult(start time.Time, err error, cacheHit bool) {
+	if c.health == nil {
+		return
+	}
+	result := ProviderResult{Latency: time.Since(start), CacheHit: cacheHit}
+	if err == nil {
+		result.Ok = true
+	} else {
+		result.ErrorClass = priceProviderErrorClass(err)
+		result.ErrorMsg = err.Error()
+	}
+	c.health.Record("prices", result)
+}
+
+// priceProviderErrorClass classifies a Bars error for HealthRegistry.
+func priceProviderErrorClass(err error) string {
+	var statusErr priceProviderStatusError
+	var parseErr pythonParseError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &parseErr):
+		return ErrorClassParse
+	case errors.As(err, &statusErr):
+		if statusErr.status >= 500 {
+			return ErrorClass5xx
+		}
+		return ErrorClass4xx
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	default:
+		return ErrorClass5xx
+	}
+}
+
+func barsCacheKey(symbol string, from, to time.Time) string {
+	return fmt.Sprintf("bars:v1:%s:%d:%d", strings.ToUpper(symbol), from.Unix(), to.Unix())
+}
+
+func barsOutcome(status int) string {
+	if status >= 500 {
+		return "upstream_5xx"
+	}
+	return "upstream_4xx"
+}
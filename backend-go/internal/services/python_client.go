@@ -1,25 +1,45 @@
 package services
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
+	"net"
 	"net/http"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"macroquant-intel/backend-go/internal/config"
 	"macroquant-intel/backend-go/internal/models"
+	"macroquant-intel/backend-go/internal/reqlog"
 )
 
 type PythonClient struct {
-	baseURL      string
-	hc           *http.Client
-	intelTimeout time.Duration
-	cb           *circuitBreaker
+	baseURL         string
+	hc              *http.Client
+	intelTimeout    time.Duration
+	streamDeadline  time.Duration
+	streamHeartbeat time.Duration
+	breaker         *Breaker
+	health          *HealthRegistry
+
+	// RetryPolicy overrides which failures RunIntel and WithRetry retry.
+	// Nil uses defaultRetryPolicy.
+	RetryPolicy RetryPolicy
+
+	hedgePercentile    float64
+	hedgeFallbackDelay time.Duration
+	hedgeMinSamples    int
+
+	latencyMu sync.Mutex
+	latencies map[string]*latencyRing
 }
 
 type UpstreamError struct {
@@ -31,57 +51,280 @@ func (e *UpstreamError) Error() string {
 	return fmt.Sprintf("python api: %d", e.Status)
 }
 
-type circuitBreaker struct {
-	mu        sync.Mutex
-	failures  int
-	threshold int
-	openedAt  time.Time
-	cooldown  time.Duration
+func NewPythonClient(cfg config.Config) *PythonClient {
+	percentile := cfg.HedgePercentile
+	if percentile <= 0 || percentile >= 1 {
+		percentile = 0.95
+	}
+	fallbackDelay := cfg.HedgeFallbackDelay
+	if fallbackDelay <= 0 {
+		fallbackDelay = 150 * time.Millisecond
+	}
+	minSamples := cfg.HedgeMinSamples
+	if minSamples <= 0 {
+		minSamples = 8
+	}
+	return &PythonClient{
+		baseURL: cfg.PyBaseURL,
+		hc: &http.Client{
+			Timeout: cfg.RequestTimeout,
+		},
+		intelTimeout:       cfg.IntelTimeout,
+		streamDeadline:     cfg.IntelStreamDeadline,
+		streamHeartbeat:    cfg.IntelStreamHeartbeat,
+		breaker:            NewBreaker(cfg),
+		hedgePercentile:    percentile,
+		hedgeFallbackDelay: fallbackDelay,
+		hedgeMinSamples:    minSamples,
+		latencies:          make(map[string]*latencyRing),
+	}
+}
+
+// BreakerStats reports every upstream path's circuit state and trip/probe
+// counts, for a debug endpoint (see /debug/breaker).
+func (c *PythonClient) BreakerStats() map[string]BreakerKeyStats {
+	return c.breaker.Stats()
 }
 
-func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
-	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+// SetHealthRegistry attaches h so FetchJSONWithTimeout records its
+// outcomes under the "python_intel" source. Optional: a PythonClient with
+// no registry attached just skips recording.
+func (c *PythonClient) SetHealthRegistry(h *HealthRegistry) {
+	c.health = h
 }
 
-func (c *circuitBreaker) allow() bool {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	if c.failures < c.threshold {
-		return true
+// breakerKey derives the Breaker key for path, stripping any query string
+// so "/portfolio?base=USD" and "/portfolio?base=TRY" share one circuit and
+// one bulkhead.
+func breakerKey(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// guarded runs fn behind the Breaker entry for path: it acquires a
+// bulkhead slot, fails fast with ErrCircuitOpen if the circuit is open,
+// records fn's latency into the path's latencyRing (see Latencies), and
+// records fn's outcome against the circuit before releasing the slot.
+func (c *PythonClient) guarded(ctx context.Context, path string, fn func() error) error {
+	key := breakerKey(path)
+	release, err := c.breaker.Acquire(ctx, key)
+	if err != nil {
+		return err
 	}
-	if time.Since(c.openedAt) > c.cooldown {
-		c.failures = 0
-		c.openedAt = time.Time{}
-		return true
+	defer release()
+	if !c.breaker.Allow(key) {
+		return ErrCircuitOpen
+	}
+	start := time.Now()
+	err = fn()
+	c.recordLatency(key, time.Since(start))
+	if err != nil {
+		c.breaker.Fail(key)
+		return err
 	}
-	return false
+	c.breaker.Success(key)
+	return nil
 }
 
-func (c *circuitBreaker) success() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.failures = 0
-	c.openedAt = time.Time{}
+// latencyRingSize bounds how many recent latencies a latencyRing keeps
+// per path, so Latencies() reflects recent behavior without an unbounded
+// history.
+const latencyRingSize = 64
+
+// latencyRing is a fixed-size ring buffer of recent call latencies for
+// one upstream path, backing Latencies and the hedge-delay calculation in
+// FetchJSONWithStatusHedged.
+type latencyRing struct {
+	mu     sync.Mutex
+	window [latencyRingSize]time.Duration
+	next   int
+	count  int
 }
 
-func (c *circuitBreaker) fail() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.failures++
-	if c.failures >= c.threshold {
-		c.openedAt = time.Now()
+func (r *latencyRing) add(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.window[r.next] = d
+	r.next = (r.next + 1) % latencyRingSize
+	if r.count < latencyRingSize {
+		r.count++
 	}
 }
 
-func NewPythonClient(cfg config.Config) *PythonClient {
-	return &PythonClient{
-		baseURL: cfg.PyBaseURL,
-		hc: &http.Client{
-			Timeout: cfg.RequestTimeout,
-		},
-		intelTimeout: cfg.IntelTimeout,
-		cb:           newCircuitBreaker(cfg.CircuitFailLimit, cfg.CircuitCooldown),
+func (r *latencyRing) snapshot() []time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]time.Duration, r.count)
+	copy(out, r.window[:r.count])
+	return out
+}
+
+// recordLatency appends d to key's latencyRing, creating it on first use.
+func (c *PythonClient) recordLatency(key string, d time.Duration) {
+	c.latencyMu.Lock()
+	ring, ok := c.latencies[key]
+	if !ok {
+		ring = &latencyRing{}
+		c.latencies[key] = ring
+	}
+	c.latencyMu.Unlock()
+	ring.add(d)
+}
+
+// Latencies returns a snapshot of path's recent call latencies (up to the
+// last latencyRingSize calls, in no particular order), for callers that
+// want to inspect what FetchJSONWithStatusHedged is basing its hedge
+// delay on.
+func (c *PythonClient) Latencies(path string) []time.Duration {
+	key := breakerKey(path)
+	c.latencyMu.Lock()
+	ring, ok := c.latencies[key]
+	c.latencyMu.Unlock()
+	if !ok {
+		return nil
+	}
+	return ring.snapshot()
+}
+
+// latencyPercentile returns key's p-th percentile latency (0 <= p < 1)
+// over its recorded samples, or 0 if fewer than hedgeMinSamples have been
+// recorded yet: with too little history a percentile is noise, so callers
+// should fall back to a fixed delay instead.
+func (c *PythonClient) latencyPercentile(key string, p float64) time.Duration {
+	samples := c.Latencies(key)
+	if len(samples) < c.hedgeMinSamples {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := int(p * float64(len(sorted)))
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// hedgeDelay returns how long FetchJSONWithStatusHedged should wait before
+// firing a second request for key: the configured percentile of recent
+// latencies, or hedgeFallbackDelay if there isn't enough history yet.
+func (c *PythonClient) hedgeDelay(key string) time.Duration {
+	if d := c.latencyPercentile(key, c.hedgePercentile); d > 0 {
+		return d
+	}
+	return c.hedgeFallbackDelay
+}
+
+// doGET performs a single GET against path and returns its status and raw
+// body, wrapping a non-2xx status in an *UpstreamError the same way
+// FetchJSONWithStatus does.
+func (c *PythonClient) doGET(ctx context.Context, path string) (status int, body []byte, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	res, err := c.hc.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+	status = res.StatusCode
+	body, err = io.ReadAll(io.LimitReader(res.Body, 4<<20))
+	if err != nil {
+		return status, nil, err
+	}
+	if status >= 300 {
+		return status, body, &UpstreamError{Status: status, Body: string(body)}
+	}
+	return status, body, nil
+}
+
+// raceGET runs doGET against path, firing a second identical request
+// against raceCtx if the first hasn't returned within delay, and returns
+// whichever completes first. The second request only fires if it can
+// acquire its own bulkhead slot via Breaker.TryAcquire; callers already
+// hold one slot for the first attempt (see FetchJSONWithStatusHedged), so
+// a hedge that fired without a slot of its own would let concurrency
+// against key reach 2x MaxInflightPerUpstream right when the upstream is
+// already struggling - the exact condition hedging is meant to help with.
+// If no second slot is free, the hedge is skipped and raceGET just waits
+// on the first attempt. Every attempt's latency is recorded regardless of
+// which wins, since a hedge's whole point is to reduce tail latency for
+// the response the caller actually waits on, not to hide slow attempts
+// from Latencies.
+func (c *PythonClient) raceGET(ctx, raceCtx context.Context, path string, delay time.Duration) (status int, body []byte, err error) {
+	key := breakerKey(path)
+	type result struct {
+		status int
+		body   []byte
+		err    error
+	}
+	results := make(chan result, 2)
+	fire := func() {
+		start := time.Now()
+		st, b, e := c.doGET(raceCtx, path)
+		c.recordLatency(key, time.Since(start))
+		results <- result{st, b, e}
+	}
+	go fire()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	hedged := false
+	for {
+		select {
+		case r := <-results:
+			return r.status, r.body, r.err
+		case <-timer.C:
+			if !hedged {
+				hedged = true
+				if release, ok := c.breaker.TryAcquire(key); ok {
+					go func() {
+						defer release()
+						fire()
+					}()
+				}
+			}
+		case <-ctx.Done():
+			return 0, nil, ctx.Err()
+		}
+	}
+}
+
+// FetchJSONWithStatusHedged is FetchJSONWithStatus, but fires a second
+// identical GET if the first hasn't returned within the path's recent p95
+// latency (see hedgeDelay) and returns whichever completes first,
+// cancelling the loser. This trades one extra request on the (rare) slow
+// tail for a much lower p99 against an upstream whose stalls - e.g. a
+// Python GC pause - are usually transient and don't affect a second,
+// concurrent attempt. GET-only: safe to hedge because repeating it has no
+// side effects.
+func (c *PythonClient) FetchJSONWithStatusHedged(ctx context.Context, path string, out any) (int, error) {
+	key := breakerKey(path)
+	release, err := c.breaker.Acquire(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	if !c.breaker.Allow(key) {
+		return 0, ErrCircuitOpen
 	}
+
+	raceCtx, cancel := context.WithCancel(ctx)
+	defer cancel() // stops whichever attempt didn't win
+
+	status, body, err := c.raceGET(ctx, raceCtx, path, c.hedgeDelay(key))
+	if err != nil {
+		c.breaker.Fail(key)
+		return status, err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		c.breaker.Fail(key)
+		return status, pythonParseError{err}
+	}
+	c.breaker.Success(key)
+	return status, nil
 }
 
 func (c *PythonClient) Health(ctx context.Context) error {
@@ -100,10 +343,121 @@ func (c *PythonClient) Health(ctx context.Context) error {
 	return nil
 }
 
+const (
+	// retryMaxAttempts bounds how many times a retrying call (RunIntel, or
+	// WithRetry) will try before giving up.
+	retryMaxAttempts = 3
+	// retryBackoffBase and retryBackoffCap bound the full-jitter backoff
+	// between attempts: sleep = rand(0, min(retryBackoffCap,
+	// retryBackoffBase*2^attempt)).
+	retryBackoffBase = 300 * time.Millisecond
+	retryBackoffCap  = 3 * time.Second
+)
+
+// RetryPolicy decides whether a failed attempt (status is the HTTP status
+// observed, or 0 if the request never got a response) is worth retrying.
+// PythonClient's RetryPolicy field defaults to defaultRetryPolicy, which
+// retries network errors, 429, and 5xx, but never another 4xx: a validation error
+// will fail the same way on every attempt, so retrying it just spends two
+// more round-trips to learn nothing.
+type RetryPolicy func(status int, err error) bool
+
+// defaultRetryPolicy is used by RunIntel and WithRetry when
+// PythonClient.RetryPolicy is nil.
+func defaultRetryPolicy(status int, err error) bool {
+	var parseErr pythonParseError
+	if errors.As(err, &parseErr) {
+		return false
+	}
+	var upstream *UpstreamError
+	if errors.As(err, &upstream) {
+		status = upstream.Status
+	} else if err != nil {
+		return true // transport-level error: timeout, connection reset, DNS, etc.
+	}
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// fullJitterBackoff returns a random duration in [0, min(cap,
+// base*2^attempt)], both ends included, per the "full jitter" strategy: spreading retries
+// across the whole window (rather than sleeping exactly base*2^attempt)
+// avoids every caller that failed at the same moment retrying in lockstep.
+func fullJitterBackoff(base, cap time.Duration, attempt int) time.Duration {
+	backoff := base * time.Duration(uint64(1)<<uint(attempt))
+	if backoff <= 0 || backoff > cap {
+		backoff = cap
+	}
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// retryBudgetOK reports whether ctx has enough time left before its own
+// deadline to sleep wait and still run one more attempt, so the retry loop
+// doesn't start an attempt it knows the caller's context will cancel
+// partway through.
+func (c *PythonClient) retryBudgetOK(ctx context.Context, wait time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return true
+	}
+	needed := wait
+	if c.intelTimeout > 0 {
+		needed += c.intelTimeout
+	}
+	return time.Until(deadline) > needed
+}
+
+// retryingDo runs fn, one HTTP attempt that reports the status it
+// observed (0 if none), up to retryMaxAttempts times: it stops as soon as
+// fn succeeds, c.RetryPolicy says the failure isn't retryable, or
+// retryBudgetOK says the next attempt wouldn't fit before ctx's deadline,
+// backing off with fullJitterBackoff between attempts.
+func (c *PythonClient) retryingDo(ctx context.Context, fn func() (status int, err error)) error {
+	policy := c.RetryPolicy
+	if policy == nil {
+		policy = defaultRetryPolicy
+	}
+	var lastErr error
+	for attempt := 0; attempt < retryMaxAttempts; attempt++ {
+		status, err := fn()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if attempt == retryMaxAttempts-1 || !policy(status, err) {
+			break
+		}
+		wait := fullJitterBackoff(retryBackoffBase, retryBackoffCap, attempt)
+		if !c.retryBudgetOK(ctx, wait) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	return lastErr
+}
+
+// WithRetry applies the same budget-aware, full-jitter retry behavior as
+// RunIntel to an arbitrary upstream call. FetchJSONWithStatus and
+// PostJSONWithStatus don't call this themselves, since retrying a POST or
+// DELETE by default risks re-running a non-idempotent request; callers
+// that know their own request is safe to retry can wrap it with this to
+// opt in.
+func (c *PythonClient) WithRetry(ctx context.Context, fn func() (status int, err error)) error {
+	return c.retryingDo(ctx, fn)
+}
+
 func (c *PythonClient) RunIntel(ctx context.Context, req models.IntelRequest) (models.IntelResponse, error) {
 	var out models.IntelResponse
-	if !c.cb.allow() {
-		return out, errors.New("python circuit breaker open")
+	release, err := c.breaker.Acquire(ctx, "/intel")
+	if err != nil {
+		return out, err
+	}
+	defer release()
+	if !c.breaker.Allow("/intel") {
+		return out, ErrCircuitOpen
 	}
 
 	payload, err := json.Marshal(req)
@@ -116,193 +470,374 @@ func (c *PythonClient) RunIntel(ctx context.Context, req models.IntelRequest) (m
 	if c.intelTimeout > 0 {
 		intelClient.Timeout = c.intelTimeout
 	}
-	var lastErr error
-	for attempt := 0; attempt < 3; attempt++ {
+
+	err = c.retryingDo(ctx, func() (int, error) {
 		hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
 		if err != nil {
-			return out, err
+			return 0, err
 		}
 		hreq.Header.Set("Content-Type", "application/json")
 
 		res, err := intelClient.Do(hreq)
 		if err != nil {
-			lastErr = err
-			select {
-			case <-ctx.Done():
-				c.cb.fail()
-				return out, ctx.Err()
-			case <-time.After(time.Duration(attempt+1) * 300 * time.Millisecond):
-				continue
-			}
+			return 0, err
 		}
+		defer res.Body.Close()
 
 		if res.StatusCode >= 300 {
-			lastErr = fmt.Errorf("python intel: %s", res.Status)
-			res.Body.Close()
-			select {
-			case <-ctx.Done():
-				c.cb.fail()
-				return out, ctx.Err()
-			case <-time.After(time.Duration(attempt+1) * 300 * time.Millisecond):
-				continue
-			}
+			return res.StatusCode, &UpstreamError{Status: res.StatusCode}
 		}
-
 		if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
-			lastErr = err
-			res.Body.Close()
-			select {
-			case <-ctx.Done():
-				c.cb.fail()
-				return out, ctx.Err()
-			case <-time.After(time.Duration(attempt+1) * 300 * time.Millisecond):
-				continue
-			}
+			return res.StatusCode, pythonParseError{err}
 		}
-
-		res.Body.Close()
-		c.cb.success()
-		return out, nil
+		return res.StatusCode, nil
+	})
+	if err != nil {
+		c.breaker.Fail("/intel")
+		return out, err
 	}
-
-	c.cb.fail()
-	return out, lastErr
+	c.breaker.Success("/intel")
+	return out, nil
 }
 
-func (c *PythonClient) FetchJSON(ctx context.Context, path string, out any) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+// errStreamHeartbeatMissed is returned by StreamIntel when no event (data
+// or heartbeat) arrives within the heartbeat deadline, which is treated as
+// the connection having died silently.
+var errStreamHeartbeatMissed = errors.New("python intel stream: heartbeat missed")
+
+// IntelEventHandler processes one event from a StreamIntel call. Returning
+// an error stops the stream and is surfaced as StreamIntel's error.
+type IntelEventHandler func(event models.IntelEvent) error
+
+// StreamIntel POSTs to /intel/run?stream=1 and invokes handle for each
+// newline-delimited IntelEvent as it arrives, instead of buffering the
+// full IntelResponse the way RunIntel does. This unblocks callers that
+// want live progress out of a multi-minute Monte-Carlo or backtest run
+// rather than waiting on RunIntel's blocking 3-retry call.
+//
+// Go's http.Server write timeouts silently truncate a response that goes
+// quiet for too long, so the Python side is expected to emit a "heartbeat"
+// event at least every streamHeartbeat interval; if one is missed this
+// treats the connection as dead, fails the breaker, and returns
+// errStreamHeartbeatMissed. The call is also bounded overall by
+// streamDeadline regardless of how often heartbeats arrive.
+func (c *PythonClient) StreamIntel(ctx context.Context, req models.IntelRequest, handle IntelEventHandler) error {
+	key := "/intel"
+	release, err := c.breaker.Acquire(ctx, key)
 	if err != nil {
 		return err
 	}
-	res, err := c.hc.Do(req)
+	defer release()
+	if !c.breaker.Allow(key) {
+		return ErrCircuitOpen
+	}
+
+	payload, err := json.Marshal(req)
 	if err != nil {
 		return err
 	}
-	defer res.Body.Close()
-	if res.StatusCode >= 300 {
-		return fmt.Errorf("python api: %s", res.Status)
+
+	deadline := c.streamDeadline
+	if deadline <= 0 {
+		deadline = c.intelTimeout
+	}
+	streamCtx := ctx
+	if deadline > 0 {
+		var cancel context.CancelFunc
+		streamCtx, cancel = context.WithTimeout(ctx, deadline)
+		defer cancel()
 	}
-	return json.NewDecoder(res.Body).Decode(out)
-}
 
-func (c *PythonClient) FetchJSONWithTimeout(ctx context.Context, path string, out any, timeout time.Duration) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	hreq, err := http.NewRequestWithContext(streamCtx, http.MethodPost, c.baseURL+"/intel/run?stream=1", bytes.NewReader(payload))
 	if err != nil {
+		c.breaker.Fail(key)
 		return err
 	}
-	hc := *c.hc
-	hc.Timeout = timeout
-	res, err := hc.Do(req)
+	hreq.Header.Set("Content-Type", "application/json")
+	hreq.Header.Set("Accept", "text/event-stream")
+
+	// The per-heartbeat deadline below, not http.Client.Timeout, governs
+	// idleness here: a fixed client timeout would cut off a slow-but-alive
+	// multi-minute run.
+	streamClient := *c.hc
+	streamClient.Timeout = 0
+	res, err := streamClient.Do(hreq)
 	if err != nil {
+		c.breaker.Fail(key)
 		return err
 	}
 	defer res.Body.Close()
 	if res.StatusCode >= 300 {
-		return fmt.Errorf("python api: %s", res.Status)
+		c.breaker.Fail(key)
+		return &UpstreamError{Status: res.StatusCode}
 	}
-	return json.NewDecoder(res.Body).Decode(out)
-}
 
-func (c *PythonClient) FetchJSONWithStatus(ctx context.Context, path string, out any) (int, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
-	if err != nil {
-		return 0, err
-	}
-	res, err := c.hc.Do(req)
-	if err != nil {
-		return 0, err
+	heartbeat := c.streamHeartbeat
+	if heartbeat <= 0 {
+		heartbeat = 30 * time.Second
 	}
-	defer res.Body.Close()
-	status := res.StatusCode
-	if status >= 300 {
-		body, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
-		return status, &UpstreamError{Status: status, Body: string(body)}
-	}
-	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
-		return status, err
+
+	events := make(chan models.IntelEvent)
+	scanErr := make(chan error, 1)
+	go func() {
+		defer close(events)
+		scanner := bufio.NewScanner(res.Body)
+		scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			line = bytes.TrimPrefix(line, []byte("data:"))
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
+			var evt models.IntelEvent
+			if err := json.Unmarshal(line, &evt); err != nil {
+				scanErr <- err
+				return
+			}
+			events <- evt
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	timer := time.NewTimer(heartbeat)
+	defer timer.Stop()
+	for {
+		select {
+		case evt, ok := <-events:
+			if !ok {
+				if err := <-scanErr; err != nil {
+					c.breaker.Fail(key)
+					return err
+				}
+				c.breaker.Success(key)
+				return nil
+			}
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(heartbeat)
+			if evt.Type == "heartbeat" {
+				continue
+			}
+			if err := handle(evt); err != nil {
+				c.breaker.Fail(key)
+				return err
+			}
+		case <-timer.C:
+			c.breaker.Fail(key)
+			return errStreamHeartbeatMissed
+		case <-streamCtx.Done():
+			c.breaker.Fail(key)
+			return streamCtx.Err()
+		}
 	}
-	return status, nil
 }
 
-func (c *PythonClient) FetchJSONWithStatusTimeout(ctx context.Context, path string, out any, timeout time.Duration) (int, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
-	if err != nil {
-		return 0, err
-	}
-	hc := *c.hc
-	hc.Timeout = timeout
-	res, err := hc.Do(req)
+func (c *PythonClient) FetchJSON(ctx context.Context, path string, out any) error {
+	return c.guarded(ctx, path, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+		if err != nil {
+			return err
+		}
+		res, err := c.hc.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.StatusCode >= 300 {
+			return fmt.Errorf("python api: %s", res.Status)
+		}
+		return json.NewDecoder(res.Body).Decode(out)
+	})
+}
+
+func (c *PythonClient) FetchJSONWithTimeout(ctx context.Context, path string, out any, timeout time.Duration) error {
+	start := time.Now()
+	url := c.baseURL + path
+	status := 0
+	err := c.guarded(ctx, path, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		hc := *c.hc
+		hc.Timeout = timeout
+		res, err := hc.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		status = res.StatusCode
+		if res.StatusCode >= 300 {
+			return &UpstreamError{Status: res.StatusCode}
+		}
+		if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+			return pythonParseError{err}
+		}
+		return nil
+	})
+	c.recordResult(start, err)
+	attempt := reqlog.UpstreamAttempt{URL: url, Attempt: 1, Status: status, RateLimited: status == http.StatusTooManyRequests}
 	if err != nil {
-		return 0, err
+		attempt.Err = err.Error()
 	}
-	defer res.Body.Close()
-	status := res.StatusCode
-	if status >= 300 {
-		body, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
-		return status, &UpstreamError{Status: status, Body: string(body)}
-	}
-	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
-		return status, err
+	reqlog.FromContext(ctx).RecordUpstream(attempt)
+	return err
+}
+
+// pythonParseError marks a FetchJSONWithTimeout decode failure so
+// pythonErrorClass can tell it apart from a transport or status error.
+type pythonParseError struct{ err error }
+
+func (e pythonParseError) Error() string { return e.err.Error() }
+func (e pythonParseError) Unwrap() error { return e.err }
+
+// recordResult reports a FetchJSONWithTimeout outcome to the attached
+// HealthRegistry, if any.
+func (c *PythonClient) recordResult(start time.Time, err error) {
+	if c.health == nil {
+		return
+	}
+	result := ProviderResult{Latency: time.Since(start)}
+	if err == nil {
+		result.Ok = true
+	} else {
+		result.ErrorClass = pythonErrorClass(err)
+		result.ErrorMsg = err.Error()
+	}
+	c.health.Record("python_intel", result)
+}
+
+// pythonErrorClass classifies a FetchJSONWithTimeout error for
+// HealthRegistry.
+func pythonErrorClass(err error) string {
+	var upstream *UpstreamError
+	var parseErr pythonParseError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		return ErrorClassCircuitOpen
+	case errors.As(err, &parseErr):
+		return ErrorClassParse
+	case errors.As(err, &upstream):
+		if upstream.Status >= 500 {
+			return ErrorClass5xx
+		}
+		return ErrorClass4xx
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	default:
+		return ErrorClass5xx
 	}
-	return status, nil
+}
+
+func (c *PythonClient) FetchJSONWithStatus(ctx context.Context, path string, out any) (int, error) {
+	var status int
+	err := c.guarded(ctx, path, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+		if err != nil {
+			return err
+		}
+		res, err := c.hc.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		status = res.StatusCode
+		if status >= 300 {
+			body, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+			return &UpstreamError{Status: status, Body: string(body)}
+		}
+		return json.NewDecoder(res.Body).Decode(out)
+	})
+	return status, err
+}
+
+func (c *PythonClient) FetchJSONWithStatusTimeout(ctx context.Context, path string, out any, timeout time.Duration) (int, error) {
+	var status int
+	err := c.guarded(ctx, path, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+		if err != nil {
+			return err
+		}
+		hc := *c.hc
+		hc.Timeout = timeout
+		res, err := hc.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		status = res.StatusCode
+		if status >= 300 {
+			body, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+			return &UpstreamError{Status: status, Body: string(body)}
+		}
+		return json.NewDecoder(res.Body).Decode(out)
+	})
+	return status, err
 }
 
 func (c *PythonClient) PostJSON(ctx context.Context, path string, body []byte, out any) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.hc.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-	if res.StatusCode >= 300 {
-		return fmt.Errorf("python api: %s", res.Status)
-	}
-	return json.NewDecoder(res.Body).Decode(out)
+	return c.guarded(ctx, path, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		res, err := c.hc.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.StatusCode >= 300 {
+			return fmt.Errorf("python api: %s", res.Status)
+		}
+		return json.NewDecoder(res.Body).Decode(out)
+	})
 }
 
 func (c *PythonClient) PostJSONWithStatus(ctx context.Context, path string, body []byte, out any) (int, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
-	if err != nil {
-		return 0, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.hc.Do(req)
-	if err != nil {
-		return 0, err
-	}
-	defer res.Body.Close()
-	status := res.StatusCode
-	if status >= 300 {
-		bodyStr, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
-		return status, &UpstreamError{Status: status, Body: string(bodyStr)}
-	}
-	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
-		return status, err
-	}
-	return status, nil
+	var status int
+	err := c.guarded(ctx, path, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		res, err := c.hc.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		status = res.StatusCode
+		if status >= 300 {
+			bodyStr, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+			return &UpstreamError{Status: status, Body: string(bodyStr)}
+		}
+		return json.NewDecoder(res.Body).Decode(out)
+	})
+	return status, err
 }
 
 func (c *PythonClient) DeleteJSONWithStatus(ctx context.Context, path string, out any) (int, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+path, nil)
-	if err != nil {
-		return 0, err
-	}
-	res, err := c.hc.Do(req)
-	if err != nil {
-		return 0, err
-	}
-	defer res.Body.Close()
-	status := res.StatusCode
-	if status >= 300 {
-		bodyStr, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
-		return status, &UpstreamError{Status: status, Body: string(bodyStr)}
-	}
-	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
-		return status, err
-	}
-	return status, nil
+	var status int
+	err := c.guarded(ctx, path, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+path, nil)
+		if err != nil {
+			return err
+		}
+		res, err := c.hc.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		status = res.StatusCode
+		if status >= 300 {
+			bodyStr, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+			return &UpstreamError{Status: status, Body: string(bodyStr)}
+		}
+		return json.NewDecoder(res.Body).Decode(out)
+	})
+	return status, err
 }
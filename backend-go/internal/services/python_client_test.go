@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"macroquant-intel/backend-go/internal/config"
+	"macroquant-intel/backend-go/internal/models"
+)
+
+func newTestPythonClient(baseURL string, streamHeartbeat time.Duration) *PythonClient {
+	cfg := config.Config{
+		PyBaseURL:              baseURL,
+		RequestTimeout:         time.Second,
+		CircuitFailLimit:       5,
+		CircuitCooldown:        time.Minute,
+		MaxInflightPerUpstream: 4,
+		IntelStreamDeadline:    time.Second,
+		IntelStreamHeartbeat:   streamHeartbeat,
+	}
+	return NewPythonClient(cfg)
+}
+
+func newHedgeTestClient(baseURL string, fallbackDelay time.Duration) *PythonClient {
+	cfg := config.Config{
+		PyBaseURL:              baseURL,
+		RequestTimeout:         2 * time.Second,
+		CircuitFailLimit:       5,
+		CircuitCooldown:        time.Minute,
+		MaxInflightPerUpstream: 4,
+		HedgeFallbackDelay:     fallbackDelay,
+		HedgeMinSamples:        8,
+	}
+	return NewPythonClient(cfg)
+}
+
+func TestStreamIntelDeliversEventsInOrder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(w, "data: {\"type\":\"progress\",\"data\":%d}\n\n", i)
+			flusher.Flush()
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestPythonClient(srv.URL, 50*time.Millisecond)
+
+	var got []string
+	err := c.StreamIntel(context.Background(), models.IntelRequest{}, func(evt models.IntelEvent) error {
+		got = append(got, string(evt.Data))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamIntel: %v", err)
+	}
+	want := []string{"0", "1", "2"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestStreamIntelHeartbeatMissedFailsStream(t *testing.T) {
+	block := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher.Flush()
+		<-block // hang past the heartbeat deadline without sending anything
+	}))
+	defer srv.Close()
+	defer close(block)
+
+	c := newTestPythonClient(srv.URL, 20*time.Millisecond)
+
+	err := c.StreamIntel(context.Background(), models.IntelRequest{}, func(evt models.IntelEvent) error {
+		return nil
+	})
+	if !errors.Is(err, errStreamHeartbeatMissed) {
+		t.Fatalf("expected errStreamHeartbeatMissed, got %v", err)
+	}
+}
+
+func TestStreamIntelHandlerErrorStopsStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(w, "data: {\"type\":\"progress\",\"data\":%d}\n\n", i)
+			flusher.Flush()
+			time.Sleep(5 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestPythonClient(srv.URL, 50*time.Millisecond)
+
+	handlerErr := errors.New("stop here")
+	calls := 0
+	err := c.StreamIntel(context.Background(), models.IntelRequest{}, func(evt models.IntelEvent) error {
+		calls++
+		return handlerErr
+	})
+	if !errors.Is(err, handlerErr) {
+		t.Fatalf("expected handlerErr, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected the stream to stop after the first event, got %d calls", calls)
+	}
+}
+
+func TestRunIntelRetriesOn5xxThenSucceeds(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"tsISO":"2026-07-30T00:00:00Z"}`)
+	}))
+	defer srv.Close()
+
+	c := newTestPythonClient(srv.URL, 0)
+	out, err := c.RunIntel(context.Background(), models.IntelRequest{})
+	if err != nil {
+		t.Fatalf("RunIntel: %v", err)
+	}
+	if out.TsISO != "2026-07-30T00:00:00Z" {
+		t.Fatalf("unexpected response: %+v", out)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestRunIntelDoesNotRetryOn4xx(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	c := newTestPythonClient(srv.URL, 0)
+	_, err := c.RunIntel(context.Background(), models.IntelRequest{})
+	if err == nil {
+		t.Fatal("expected an error from a 400 response")
+	}
+	var upstream *UpstreamError
+	if !errors.As(err, &upstream) || upstream.Status != http.StatusBadRequest {
+		t.Fatalf("expected *UpstreamError{Status: 400}, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected a single attempt for a 4xx, got %d", got)
+	}
+}
+
+func TestRunIntelHonorsCustomRetryPolicy(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	c := newTestPythonClient(srv.URL, 0)
+	c.RetryPolicy = func(status int, err error) bool { return status == http.StatusBadRequest }
+
+	_, err := c.RunIntel(context.Background(), models.IntelRequest{})
+	if err == nil {
+		t.Fatal("expected an error from a 400 response")
+	}
+	if got := atomic.LoadInt32(&calls); got != retryMaxAttempts {
+		t.Fatalf("expected a custom policy retrying 400s to use all %d attempts, got %d", retryMaxAttempts, got)
+	}
+}
+
+func TestRunIntelStopsRetryingWhenBudgetWouldBeExceeded(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := newTestPythonClient(srv.URL, 0)
+	c.intelTimeout = 2 * time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.RunIntel(ctx, models.IntelRequest{})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected the retry budget check to block a second attempt given the tight deadline and large intelTimeout, got %d calls", got)
+	}
+}
+
+func TestFetchJSONWithStatusHedgedFiresSecondRequestAfterDelay(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if n == 1 {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(2 * time.Second):
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"n":%d}`, n)
+	}))
+	defer srv.Close()
+
+	c := newHedgeTestClient(srv.URL, 20*time.Millisecond)
+
+	var out map[string]any
+	status, err := c.FetchJSONWithStatusHedged(context.Background(), "/prices", &out)
+	if err != nil {
+		t.Fatalf("FetchJSONWithStatusHedged: %v", err)
+	}
+	if status != http.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if out["n"] != float64(2) {
+		t.Fatalf("expected the hedge (second request) to win, got %+v", out)
+	}
+}
+
+func TestFetchJSONWithStatusHedgedReturnsFirstWhenFast(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true}`)
+	}))
+	defer srv.Close()
+
+	c := newHedgeTestClient(srv.URL, 200*time.Millisecond)
+
+	var out map[string]any
+	if _, err := c.FetchJSONWithStatusHedged(context.Background(), "/fx", &out); err != nil {
+		t.Fatalf("FetchJSONWithStatusHedged: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond) // give a wrongly-fired hedge a chance to show up
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected no hedge to fire for a fast response, got %d calls", got)
+	}
+}
+
+// TestFetchJSONWithStatusHedgedRespectsBulkheadCap reproduces the bug this
+// test was filed against: with only one bulkhead slot available for the
+// key, a hedge firing without acquiring its own slot would let concurrent
+// requests against the upstream reach 2, exceeding MaxInflightPerUpstream
+// exactly when the upstream is already slow enough to trigger a hedge.
+func TestFetchJSONWithStatusHedgedRespectsBulkheadCap(t *testing.T) {
+	var inflight, maxInflight int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inflight, 1)
+		for {
+			max := atomic.LoadInt32(&maxInflight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInflight, max, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		atomic.AddInt32(&inflight, -1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true}`)
+	}))
+	defer srv.Close()
+
+	cfg := config.Config{
+		PyBaseURL:              srv.URL,
+		RequestTimeout:         2 * time.Second,
+		CircuitFailLimit:       5,
+		CircuitCooldown:        time.Minute,
+		MaxInflightPerUpstream: 1,
+		HedgeFallbackDelay:     5 * time.Millisecond,
+		HedgeMinSamples:        8,
+	}
+	c := NewPythonClient(cfg)
+
+	var out map[string]any
+	if _, err := c.FetchJSONWithStatusHedged(context.Background(), "/slow", &out); err != nil {
+		t.Fatalf("FetchJSONWithStatusHedged: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&maxInflight); got > 1 {
+		t.Fatalf("expected the hedge to skip firing once the single bulkhead slot was taken, but saw %d concurrent requests", got)
+	}
+}
+
+func TestLatenciesTracksRecentCallLatencies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true}`)
+	}))
+	defer srv.Close()
+
+	c := newHedgeTestClient(srv.URL, time.Second)
+
+	var out map[string]any
+	for i := 0; i < 3; i++ {
+		if _, err := c.FetchJSONWithStatus(context.Background(), "/macro", &out); err != nil {
+			t.Fatalf("FetchJSONWithStatus: %v", err)
+		}
+	}
+
+	samples := c.Latencies("/macro")
+	if len(samples) != 3 {
+		t.Fatalf("expected 3 recorded latencies, got %d", len(samples))
+	}
+}
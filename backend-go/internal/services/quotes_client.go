@@ -5,13 +5,16 @@ import (
 	"crypto/sha1"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
 
 	"macroquant-intel/backend-go/internal/config"
+	"macroquant-intel/backend-go/internal/metrics"
 )
 
 type Quote struct {
@@ -30,17 +33,118 @@ type QuotesClient struct {
 	mu    sync.Mutex
 	last  map[string]Quote
 	backoffUntil time.Time
+
+	topicsMu sync.Mutex
+	topics   map[string]*quoteTopic
+
+	stream *alpacaStream
+}
+
+// QuoteSnapshot is one tick published to a quote topic's subscribers.
+type QuoteSnapshot struct {
+	Seq    uint64
+	TsISO  string
+	Quotes map[string]Quote
+	Err    string
+}
+
+// quoteRingSize is how many recent snapshots each topic buffers, so a
+// client reconnecting with a Last-Event-ID can be replayed the updates it
+// missed instead of silently skipping ahead.
+const quoteRingSize = 32
+
+// quoteTopic fans a single asset set's poll loop out to every SSE client
+// subscribed to it, so N clients watching the same assets share one
+// upstream poll instead of each running their own.
+type quoteTopic struct {
+	subs   map[chan QuoteSnapshot]struct{}
+	cancel context.CancelFunc
+	last   *QuoteSnapshot
+	ring   []QuoteSnapshot
+	seq    uint64
+}
+
+func (t *quoteTopic) since(afterSeq uint64) ([]QuoteSnapshot, bool) {
+	if len(t.ring) == 0 {
+		return nil, afterSeq == 0
+	}
+	if afterSeq < t.ring[0].Seq-1 {
+		return nil, false
+	}
+	out := make([]QuoteSnapshot, 0, len(t.ring))
+	for _, snap := range t.ring {
+		if snap.Seq > afterSeq {
+			out = append(out, snap)
+		}
+	}
+	return out, true
 }
 
 func NewQuotesClient(cfg config.Config, cache Cache) *QuotesClient {
-	return &QuotesClient{
+	c := &QuotesClient{
 		hc: &http.Client{
 			Timeout: cfg.RequestTimeout,
 		},
-		cache: cache,
-		ttl:   60 * time.Second,
+		cache:  cache,
+		ttl:    60 * time.Second,
+		topics: make(map[string]*quoteTopic),
 		baseURL: cfg.PyBaseURL,
 	}
+	if cfg.AlpacaStreamEnabled {
+		c.stream = newAlpacaStream(cfg, c.applyStreamQuote)
+	}
+	return c
+}
+
+// Close stops the Alpaca stream supervisor, if one is running. It is a
+// no-op when ALPACA_STREAM_ENABLED wasn't set.
+func (c *QuotesClient) Close() error {
+	if c.stream != nil {
+		return c.stream.Close()
+	}
+	return nil
+}
+
+// applyStreamQuote merges a live trade/quote price from the Alpaca stream
+// into the last known quote for symbol in place. It preserves ChangePct
+// and Currency from the most recent HTTP fetch, since the stream's frames
+// don't carry a reference price to compute a change against.
+func (c *QuotesClient) applyStreamQuote(symbol string, price float64, tsISO string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.last == nil {
+		c.last = make(map[string]Quote)
+	}
+	q := c.last[symbol]
+	q.Symbol = symbol
+	q.Price = price
+	q.UpdatedISO = tsISO
+	c.last[symbol] = q
+}
+
+// streamSnapshot returns the stream-sourced quotes for symbols if every
+// one of them is live on a connected socket, so Fetch can skip the HTTP
+// round-trip. ok is false if the stream is disabled, disconnected, or any
+// symbol isn't subscribed yet (including one just queued by
+// ensureSubscribed), in which case the caller should fall back to HTTP.
+func (c *QuotesClient) streamSnapshot(symbols []string) (out map[string]Quote, ok bool) {
+	if c.stream == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out = make(map[string]Quote, len(symbols))
+	for _, sym := range symbols {
+		if !c.stream.isFresh(sym) {
+			return nil, false
+		}
+		q, have := c.last[sym]
+		if !have {
+			return nil, false
+		}
+		out[sym] = q
+	}
+	return out, true
 }
 
 type yahooQuoteResponse struct {
@@ -59,9 +163,20 @@ func (c *QuotesClient) Fetch(ctx context.Context, symbols []string) (map[string]
 	if len(symbols) == 0 {
 		return map[string]Quote{}, nil
 	}
+	if c.stream != nil {
+		c.stream.ensureSubscribed(symbols)
+		if out, ok := c.streamSnapshot(symbols); ok {
+			return out, nil
+		}
+	}
 	now := time.Now()
 	c.mu.Lock()
-	if !c.backoffUntil.IsZero() && now.Before(c.backoffUntil) {
+	inBackoff := !c.backoffUntil.IsZero() && now.Before(c.backoffUntil)
+	if !inBackoff && !c.backoffUntil.IsZero() {
+		c.backoffUntil = time.Time{}
+		metrics.QuotesBackoffActive.Set(0)
+	}
+	if inBackoff {
 		cached := cloneQuotes(c.last)
 		c.mu.Unlock()
 		if len(cached) > 0 {
@@ -93,11 +208,11 @@ func (c *QuotesClient) Fetch(ctx context.Context, symbols []string) (map[string]
 
 	res, err := c.hc.Do(req)
 	if err != nil {
-		return c.staleOrError(err)
+		return c.staleOrError(err, 0)
 	}
 	defer res.Body.Close()
 	if res.StatusCode >= 300 {
-		return c.staleOrError(fmt.Errorf("quotes service: %s", res.Status))
+		return c.staleOrError(fmt.Errorf("quotes service: %s", res.Status), res.StatusCode)
 	}
 
 	var payload struct {
@@ -109,7 +224,7 @@ func (c *QuotesClient) Fetch(ctx context.Context, symbols []string) (map[string]
 		} `json:"quotes"`
 	}
 	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
-		return c.staleOrError(err)
+		return c.staleOrError(err, 0)
 	}
 
 	out := make(map[string]Quote, len(payload.Quotes))
@@ -126,6 +241,7 @@ func (c *QuotesClient) Fetch(ctx context.Context, symbols []string) (map[string]
 		}
 	}
 
+	metrics.UpstreamRequests.WithLabelValues("quotes", "ok").Inc()
 	c.storeLast(out)
 	if c.cache != nil {
 		if b, err := MarshalCache(out); err == nil {
@@ -150,7 +266,13 @@ func (c *QuotesClient) storeLast(data map[string]Quote) {
 	c.mu.Unlock()
 }
 
-func (c *QuotesClient) staleOrError(err error) (map[string]Quote, error) {
+// staleOrError records err against upstream_requests_total (client="quotes")
+// and falls back to the last known-good quotes if any are cached, so a
+// transient upstream failure doesn't blank out the client. status is the
+// upstream HTTP status if one was received, or 0 for a transport-level
+// failure (network error, decode error).
+func (c *QuotesClient) staleOrError(err error, status int) (map[string]Quote, error) {
+	metrics.UpstreamRequests.WithLabelValues("quotes", quotesOutcome(err, status)).Inc()
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if len(c.last) == 0 {
@@ -159,6 +281,26 @@ func (c *QuotesClient) staleOrError(err error) (map[string]Quote, error) {
 	return cloneQuotes(c.last), err
 }
 
+func quotesOutcome(err error, status int) string {
+	if status >= 500 {
+		return "upstream_5xx"
+	}
+	if status >= 400 {
+		return "upstream_4xx"
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "timeout"
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	return "network"
+}
+
+// setBackoff extends the window during which Fetch serves the last known
+// quotes instead of calling the upstream provider, and raises
+// quotes_backoff_active until the window passes.
 func (c *QuotesClient) setBackoff(d time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -166,6 +308,7 @@ func (c *QuotesClient) setBackoff(d time.Duration) {
 	if until.After(c.backoffUntil) {
 		c.backoffUntil = until
 	}
+	metrics.QuotesBackoffActive.Set(1)
 }
 
 func cloneQuotes(data map[string]Quote) map[string]Quote {
@@ -178,3 +321,104 @@ func cloneQuotes(data map[string]Quote) map[string]Quote {
 	}
 	return out
 }
+
+// Subscribe attaches to the poll topic for symbols, starting it if this is
+// the first subscriber. lastEventID is the seq the caller last saw (0 if
+// none): when non-zero, replay holds the buffered snapshots newer than it
+// and ok reports whether the ring buffer still reached back that far; when
+// zero, replay holds just the latest known snapshot (if any) so a fresh
+// connection isn't left waiting a full interval for its first update.
+func (c *QuotesClient) Subscribe(ctx context.Context, symbols []string, interval time.Duration, lastEventID uint64) (ch <-chan QuoteSnapshot, unsubscribe func(), replay []QuoteSnapshot, ok bool) {
+	key := quotesCacheKey(symbols)
+	out := make(chan QuoteSnapshot, 1)
+	var once sync.Once
+
+	c.topicsMu.Lock()
+	topic := c.topics[key]
+	if topic == nil {
+		bgCtx, cancel := context.WithCancel(context.Background())
+		topic = &quoteTopic{subs: make(map[chan QuoteSnapshot]struct{}), cancel: cancel}
+		c.topics[key] = topic
+		go c.runQuoteTopic(bgCtx, key, symbols, interval)
+	}
+	topic.subs[out] = struct{}{}
+
+	ok = true
+	if lastEventID > 0 {
+		replay, ok = topic.since(lastEventID)
+	} else if topic.last != nil {
+		replay = []QuoteSnapshot{*topic.last}
+	}
+	c.topicsMu.Unlock()
+
+	unsubscribeFn := func() {
+		once.Do(func() {
+			c.topicsMu.Lock()
+			if t := c.topics[key]; t != nil {
+				delete(t.subs, out)
+				if len(t.subs) == 0 {
+					t.cancel()
+					delete(c.topics, key)
+				}
+			}
+			c.topicsMu.Unlock()
+			close(out)
+		})
+	}
+
+	go func() {
+		<-ctx.Done()
+		unsubscribeFn()
+	}()
+
+	return out, unsubscribeFn, replay, ok
+}
+
+// runQuoteTopic polls Fetch on interval and publishes each result to the
+// topic's subscribers until ctx is canceled (the last subscriber left).
+func (c *QuotesClient) runQuoteTopic(ctx context.Context, key string, symbols []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	produce := func() {
+		reqCtx, cancel := context.WithTimeout(context.Background(), c.hc.Timeout)
+		defer cancel()
+		quotes, err := c.Fetch(reqCtx, symbols)
+		snap := QuoteSnapshot{TsISO: time.Now().UTC().Format(time.RFC3339), Quotes: quotes}
+		if err != nil {
+			snap.Err = err.Error()
+		}
+		c.publish(key, snap)
+	}
+
+	produce()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			produce()
+		}
+	}
+}
+
+func (c *QuotesClient) publish(key string, snap QuoteSnapshot) {
+	c.topicsMu.Lock()
+	topic := c.topics[key]
+	if topic != nil {
+		topic.seq++
+		snap.Seq = topic.seq
+		topic.last = &snap
+		topic.ring = append(topic.ring, snap)
+		if len(topic.ring) > quoteRingSize {
+			topic.ring = topic.ring[len(topic.ring)-quoteRingSize:]
+		}
+		for ch := range topic.subs {
+			select {
+			case ch <- snap:
+			default:
+			}
+		}
+	}
+	c.topicsMu.Unlock()
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"macroquant-intel/backend-go/internal/config"
+)
+
+func newTestRedisCache(t *testing.T, prefix string) *RedisCache {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return &RedisCache{client: client, prefix: prefix}
+}
+
+func TestRedisCacheRoundTripsAndPrefixesKeys(t *testing.T) {
+	r := newTestRedisCache(t, "bknd:")
+	ctx := context.Background()
+
+	if err := r.Set(ctx, "market:v1", []byte("payload"), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	b, ok := r.Get(ctx, "market:v1")
+	if !ok || string(b) != "payload" {
+		t.Fatalf("expected payload back, got %q ok=%v", b, ok)
+	}
+
+	raw, err := r.client.Get(ctx, "bknd:market:v1").Bytes()
+	if err != nil || string(raw) != "payload" {
+		t.Fatalf("expected key stored under prefixed name, got %q err=%v", raw, err)
+	}
+
+	if err := r.Del(ctx, "market:v1"); err != nil {
+		t.Fatalf("del: %v", err)
+	}
+	if _, ok := r.Get(ctx, "market:v1"); ok {
+		t.Fatal("expected key to be gone after Del")
+	}
+}
+
+func TestRedisCachePingReportsReachability(t *testing.T) {
+	r := newTestRedisCache(t, "")
+	if err := r.Ping(context.Background()); err != nil {
+		t.Fatalf("expected miniredis to be reachable, got %v", err)
+	}
+}
+
+func TestNearCacheServesHotKeysWithoutRedisRoundTrip(t *testing.T) {
+	r := newTestRedisCache(t, "")
+	n := newNearCache(config.Config{NearCacheMaxEntries: 10}, r)
+	defer n.Close()
+	ctx := context.Background()
+
+	if err := n.Set(ctx, "market:v1", []byte("fresh"), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	// Writing directly to Redis (bypassing the near-cache) simulates
+	// another replica updating the key; the near-cache should still serve
+	// its own short-lived copy rather than round-tripping immediately.
+	if err := r.Set(ctx, "market:v1", []byte("from-another-replica"), time.Minute); err != nil {
+		t.Fatalf("direct redis set: %v", err)
+	}
+
+	b, ok := n.Get(ctx, "market:v1")
+	if !ok || string(b) != "fresh" {
+		t.Fatalf("expected near-cache to serve its own copy %q, got %q ok=%v", "fresh", b, ok)
+	}
+}
+
+func TestNearCacheFallsBackToRedisOnLocalMiss(t *testing.T) {
+	r := newTestRedisCache(t, "")
+	n := newNearCache(config.Config{NearCacheMaxEntries: 10}, r)
+	defer n.Close()
+	ctx := context.Background()
+
+	if err := r.Set(ctx, "market:v1", []byte("from-redis"), time.Minute); err != nil {
+		t.Fatalf("redis set: %v", err)
+	}
+
+	b, ok := n.Get(ctx, "market:v1")
+	if !ok || string(b) != "from-redis" {
+		t.Fatalf("expected near-cache to fall back to redis, got %q ok=%v", b, ok)
+	}
+}
+
+func TestNearCacheDelRemovesFromBothLayers(t *testing.T) {
+	r := newTestRedisCache(t, "")
+	n := newNearCache(config.Config{NearCacheMaxEntries: 10}, r)
+	defer n.Close()
+	ctx := context.Background()
+
+	_ = n.Set(ctx, "market:v1", []byte("v"), time.Minute)
+	if err := n.Del(ctx, "market:v1"); err != nil {
+		t.Fatalf("del: %v", err)
+	}
+	if _, ok := n.local.Get(ctx, "market:v1"); ok {
+		t.Fatal("expected local copy to be gone after Del")
+	}
+	if _, ok := r.Get(ctx, "market:v1"); ok {
+		t.Fatal("expected redis copy to be gone after Del")
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+)
+
+const defaultSymbolCap = 250
+
+// SymbolRegistry resolves named categories (crypto, us_mega, defense, ...)
+// plus ad-hoc extras into a deduped, capped symbol list for the quote
+// streams. It is loaded once at startup from a JSON file and can be
+// refreshed in place via Reload, so a SIGHUP or an admin endpoint can pick
+// up an edited watchlist without a restart.
+type SymbolRegistry struct {
+	mu         sync.RWMutex
+	path       string
+	cap        int
+	categories map[string][]string
+}
+
+// NewSymbolRegistry loads categories from path (if non-empty) and falls
+// back to the built-in default set when the file is absent, empty, or
+// unreadable.
+func NewSymbolRegistry(path string, cap int) *SymbolRegistry {
+	if cap <= 0 {
+		cap = defaultSymbolCap
+	}
+	r := &SymbolRegistry{path: path, cap: cap, categories: defaultSymbolCategories()}
+	if path != "" {
+		_ = r.Reload()
+	}
+	return r
+}
+
+// Reload re-reads the registry file and swaps categories in place. It is a
+// no-op if no path was configured, and leaves the existing categories
+// untouched if the file can't be read or parsed.
+func (r *SymbolRegistry) Reload() error {
+	if r.path == "" {
+		return nil
+	}
+	b, err := os.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+	var parsed map[string][]string
+	if err := json.Unmarshal(b, &parsed); err != nil {
+		return err
+	}
+	if len(parsed) == 0 {
+		return errors.New("symbol registry file has no categories")
+	}
+	r.mu.Lock()
+	r.categories = parsed
+	r.mu.Unlock()
+	return nil
+}
+
+// Categories returns a snapshot of the category -> symbols map, e.g. for
+// the /symbols endpoint.
+func (r *SymbolRegistry) Categories() map[string][]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[string][]string, len(r.categories))
+	for k, v := range r.categories {
+		cp := make([]string, len(v))
+		copy(cp, v)
+		out[k] = cp
+	}
+	return out
+}
+
+// Resolve expands categories into their member symbols, appends extra, and
+// returns the deduped (case-insensitive) result capped at r.cap. Unknown
+// categories are silently skipped.
+func (r *SymbolRegistry) Resolve(categories []string, extra []string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[string]struct{})
+	out := make([]string, 0, r.cap)
+	add := func(sym string) bool {
+		sym = strings.TrimSpace(sym)
+		if sym == "" {
+			return true
+		}
+		key := strings.ToUpper(sym)
+		if _, ok := seen[key]; ok {
+			return true
+		}
+		seen[key] = struct{}{}
+		out = append(out, sym)
+		return len(out) < r.cap
+	}
+
+	for _, cat := range categories {
+		syms, ok := r.categories[strings.ToLower(strings.TrimSpace(cat))]
+		if !ok {
+			continue
+		}
+		for _, s := range syms {
+			if !add(s) {
+				return out
+			}
+		}
+	}
+	for _, s := range extra {
+		if !add(s) {
+			return out
+		}
+	}
+	return out
+}
+
+func defaultSymbolCategories() map[string][]string {
+	return map[string][]string{
+		"crypto": {"BTC", "ETH", "BTC-USD", "NEAR-USD", "MSTR", "COIN"},
+		"fx":     {"USDTRY=X"},
+		"tr_equity": {
+			"ASTOR.IS", "SOKM.IS", "TUPRS.IS", "ENJSA.IS", "ASELS.IS", "OTKAR.IS",
+			"SDTTR.IS", "ALTNY.IS", "ONRYT.IS", "PAPIL.IS", "PATEK.IS", "KATMR.IS", "TMSN.IS",
+		},
+		"us_mega": {
+			"AMD", "PLTR", "AAPL", "MSFT", "AMZN", "GOOGL", "META", "NVDA", "TSLA",
+			"ASML.AS", "SAP.DE", "005930.KS", "6758.T", "SHOP.TO", "ADYEN.AS", "NOKIA.HE",
+			"0700.HK", "9988.HK",
+		},
+		"energy": {
+			"XOM", "CVX", "COP", "OXY", "SLB", "EOG", "MPC", "PSX", "VLO", "SHEL",
+			"TTE", "BP", "EQNR", "PBR", "ENB", "SU.TO", "CNQ.TO", "REP.MC",
+		},
+		"financials": {
+			"JPM", "BAC", "WFC", "C", "GS", "MS", "BLK", "SCHW", "AXP", "HSBA.L",
+			"UBSG.SW", "BNP.PA", "DBK.DE", "INGA.AS", "8058.T", "SAN.MC", "BARC.L", "ZURN.SW",
+		},
+		"industrials": {
+			"CAT", "DE", "BA", "GE", "HON", "UNP", "UPS", "LMT", "RTX", "SIE.DE",
+			"AIR.PA", "DPW.DE", "VOLV-B.ST", "7203.T", "7267.T", "CP.TO", "6501.T", "SGRO.L",
+		},
+		"materials": {
+			"LIN", "APD", "SHW", "ECL", "DD", "DOW", "NUE", "FCX", "NEM", "BHP.AX",
+			"RIO.AX", "GLEN.L", "ANTO.L", "BAS.DE", "SIKA.SW", "AEM.TO", "NTR.TO", "IVN.AX",
+			"SIL", "HL",
+		},
+		"defense": {
+			"NOC", "GD", "LHX", "HII", "TDG", "AVAV", "KTOS", "BA.L", "RHM.DE",
+			"HO.PA", "LDO.MI", "SAAB-B.ST", "SAF.PA", "HAG.DE", "AM.PA",
+		},
+		"israel_security": {
+			"CHKP", "CYBR", "NICE", "ESLT", "IAI.TA", "ESLT.TA", "NICE.TA", "MGDL.TA", "FIBI.TA",
+		},
+	}
+}
@@ -0,0 +1,33 @@
+package services
+
+import "testing"
+
+func TestSymbolRegistryResolveDedupesAcrossCategoriesAndExtras(t *testing.T) {
+	r := &SymbolRegistry{cap: 250, categories: map[string][]string{
+		"crypto": {"BTC", "ETH"},
+	}}
+	got := r.Resolve([]string{"crypto"}, []string{"btc", "SOL"})
+	if len(got) != 3 {
+		t.Fatalf("expected 3 deduped symbols, got %d (%v)", len(got), got)
+	}
+}
+
+func TestSymbolRegistryResolveEnforcesCap(t *testing.T) {
+	r := &SymbolRegistry{cap: 2, categories: map[string][]string{
+		"crypto": {"BTC", "ETH", "SOL"},
+	}}
+	got := r.Resolve([]string{"crypto"}, nil)
+	if len(got) != 2 {
+		t.Fatalf("expected cap of 2, got %d (%v)", len(got), got)
+	}
+}
+
+func TestSymbolRegistryResolveSkipsUnknownCategory(t *testing.T) {
+	r := &SymbolRegistry{cap: 250, categories: map[string][]string{
+		"crypto": {"BTC"},
+	}}
+	got := r.Resolve([]string{"crypto", "does_not_exist"}, nil)
+	if len(got) != 1 || got[0] != "BTC" {
+		t.Fatalf("expected only known category symbols, got %v", got)
+	}
+}